@@ -0,0 +1,204 @@
+// Package arcade runs the arcade cabinet of day 13: it draws the output of the
+// game to a screen of tiles, and moves the joystick for the game's input.
+package arcade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/icio/adventofcode2019/grid"
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+// Cabinet is the intcode.IO of the game, keeping the screen and score as the
+// game draws them.
+type Cabinet struct {
+	Score int64
+	World *grid.Grid[Tile]
+
+	display  [3]int64
+	displayN int
+
+	Draw     func()        // Called before each joystick move, if set.
+	Joystick Joystick      // Moves the joystick, if set.
+	Moves    int           // Number of times the joystick was moved from neutral.
+	Events   *json.Encoder // Receives each tile or score as it's output.
+}
+
+// Tile is a tile of the screen.
+type Tile int64
+
+const (
+	TileEmpty  Tile = 0
+	TileWall   Tile = 1
+	TileBlock  Tile = 2
+	TilePaddle Tile = 3
+	TileBall   Tile = 4
+)
+
+func (t Tile) String() string {
+	switch t {
+	case TileEmpty:
+		return " "
+	case TileWall:
+		return "#"
+	case TileBlock:
+		return "@"
+	case TilePaddle:
+		return "M"
+	case TileBall:
+		return "o"
+	default:
+		return strconv.Itoa(int(t))
+	}
+}
+
+// Joystick positions the arcade joystick each time the game asks: -1 for left,
+// 0 for neutral and +1 for right.
+type Joystick interface {
+	Position() (int64, error)
+}
+
+// New returns a cabinet with an empty screen.
+func New() *Cabinet {
+	return &Cabinet{
+		World: grid.New[Tile](),
+	}
+}
+
+// Input returns the integer left-right movement of the paddle with the
+// joystick, where the position of the joystick is determined as:
+//
+// *  0: neutral position
+// * -1: left position
+// * +1: right position
+//
+// The joystick is moved by c.Joystick when set, and otherwise to follow the
+// ball with the paddle.
+func (c *Cabinet) Input() (int64, error) {
+	if c.Draw != nil {
+		c.Draw()
+	}
+	var pos int64
+	if c.Joystick != nil {
+		var err error
+		pos, err = c.Joystick.Position()
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		pos = c.align()
+	}
+	if pos != 0 {
+		c.Moves++
+	}
+	return pos, nil
+}
+
+// align returns the joystick position which moves the center of the paddle
+// towards the center of the ball.
+func (c *Cabinet) align() int64 {
+	// Ball/Paddle Left/right.
+	bl := int64(-1)
+	br := int64(-1)
+	pl := int64(-1)
+	pr := int64(-1)
+
+	// Find the left and right extents of the paddle and ball.
+	c.World.Each(func(pos grid.Point, t Tile) bool {
+		switch t {
+		case TileBall:
+			if bl == -1 || bl > pos.X {
+				bl = pos.X
+			}
+			if br == -1 || br < pos.X {
+				br = pos.X
+			}
+		case TilePaddle:
+			if pl == -1 || pl > pos.X {
+				pl = pos.X
+			}
+			if pr == -1 || pr < pos.X {
+				pr = pos.X
+			}
+		}
+		return true
+	})
+
+	// Move the joystick to keep the centers aligned.
+	bc := (bl + br) / 2
+	pc := (pl + pr) / 2
+	switch {
+	case bc < pc:
+		return -1
+	case bc > pc:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (c *Cabinet) Output(n int64) error {
+	// Collect display.
+	c.display[c.displayN] = n
+	c.displayN++
+	if c.displayN < 3 {
+		return nil
+	}
+	c.displayN = 0
+
+	// Track the output as tiles/scores.
+	x, y, t := c.display[0], c.display[1], c.display[2]
+	if x == -1 && y == 0 {
+		c.Score = t
+		if err := c.emit(ScoreEvent{t}); err != nil {
+			return err
+		}
+	} else {
+		c.World.Set(grid.Point{X: x, Y: y}, Tile(t))
+		if err := c.emit(TileEvent{x, y, Tile(t)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the number of tiles of type t in world.
+func Count(world *grid.Grid[Tile], t Tile) (n int64) {
+	world.Each(func(_ grid.Point, wt Tile) bool {
+		if wt == t {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// NewAI returns the named AI for moving the paddle: follow, predict or solve.
+// The solver also needs the program of the game it's playing.
+func NewAI(name string, c *Cabinet, prog *intcode.Prog) (Joystick, error) {
+	switch name {
+	case "follow":
+		return NewFollower(c), nil
+	case "predict":
+		return NewPredictor(c), nil
+	case "solve":
+		return NewSolver(c, prog), nil
+	default:
+		return nil, fmt.Errorf("unrecognised AI %q", name)
+	}
+}
+
+// Follower is a joystick which keeps the paddle beneath the ball.
+type Follower struct {
+	c *Cabinet
+}
+
+func NewFollower(c *Cabinet) Follower {
+	return Follower{c}
+}
+
+func (f Follower) Position() (int64, error) {
+	return f.c.align(), nil
+}
@@ -0,0 +1,56 @@
+package arcade
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TileEvent and ScoreEvent are the JSON forms of the decoded outputs of the
+// arcade, as written one per line to Cabinet.Events.
+type TileEvent struct {
+	X    int64 `json:"x"`
+	Y    int64 `json:"y"`
+	Tile Tile  `json:"tile"`
+}
+
+type ScoreEvent struct {
+	Score int64 `json:"score"`
+}
+
+var tileNames = map[Tile]string{
+	TileEmpty:  "empty",
+	TileWall:   "wall",
+	TileBlock:  "block",
+	TilePaddle: "paddle",
+	TileBall:   "ball",
+}
+
+func (t Tile) MarshalText() ([]byte, error) {
+	if name, ok := tileNames[t]; ok {
+		return []byte(name), nil
+	}
+	return []byte(strconv.FormatInt(int64(t), 10)), nil
+}
+
+func (t *Tile) UnmarshalText(b []byte) error {
+	for tt, name := range tileNames {
+		if name == string(b) {
+			*t = tt
+			return nil
+		}
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unrecognised tile %q", b)
+	}
+	*t = Tile(n)
+	return nil
+}
+
+// emit writes the event to c.Events, if set.
+func (c *Cabinet) emit(e interface{}) error {
+	if c.Events == nil {
+		return nil
+	}
+	return c.Events.Encode(e)
+}
@@ -0,0 +1,109 @@
+package arcade
+
+import "github.com/icio/adventofcode2019/grid"
+
+// Predictor is a joystick which moves the paddle to wherever the ball will next
+// come down to the paddle's row, rather than chasing the ball as it goes.
+//
+// The velocity of the ball is taken from its movement since the previous
+// frame, and its path is simulated across the world, bouncing off the walls
+// and blocks (breaking the blocks as it goes), until it comes down to the
+// paddle.
+type Predictor struct {
+	c *Cabinet
+
+	ball   grid.Point // Position of the ball in the previous frame.
+	seen   bool       // Whether ball has been set.
+	target int64      // Column at which the ball is next expected at the paddle.
+}
+
+// maxPredictSteps bounds the simulation of the ball's path, in case it never
+// comes down.
+const maxPredictSteps = 10000
+
+func NewPredictor(c *Cabinet) *Predictor {
+	return &Predictor{c: c}
+}
+
+func (pr *Predictor) Position() (int64, error) {
+	ball, ok := pr.c.Find(TileBall)
+	if !ok {
+		return 0, nil
+	}
+	paddle, ok := pr.c.Find(TilePaddle)
+	if !ok {
+		return 0, nil
+	}
+
+	// Without a velocity we can only assume the ball comes straight down.
+	pr.target = ball.X
+	if pr.seen && ball != pr.ball {
+		v := grid.Point{X: sign(ball.X - pr.ball.X), Y: sign(ball.Y - pr.ball.Y)}
+		if x, ok := pr.land(ball, v, paddle.Y); ok {
+			pr.target = x
+		}
+	}
+	pr.ball, pr.seen = ball, true
+
+	return sign(pr.target - paddle.X), nil
+}
+
+// land simulates the ball from pos moving by v each frame, returning the column
+// in which the ball will reach the row above the paddle.
+func (pr *Predictor) land(pos, v grid.Point, paddleY int64) (int64, bool) {
+	broken := make(map[grid.Point]bool)
+	solid := func(c grid.Point) bool {
+		switch pr.c.World.Get(c) {
+		case TileWall:
+			return true
+		case TileBlock:
+			return !broken[c]
+		}
+		return false
+	}
+	bounce := func(c grid.Point) bool {
+		if !solid(c) {
+			return false
+		}
+		if pr.c.World.Get(c) == TileBlock {
+			broken[c] = true
+		}
+		return true
+	}
+
+	for i := 0; i < maxPredictSteps; i++ {
+		if pos.Y == paddleY-1 && v.Y > 0 {
+			return pos.X, true
+		}
+
+		// Bounce off anything beside, above or below the ball, and then keep
+		// bouncing off anything diagonally in its way.
+		if bounce(grid.Point{X: pos.X + v.X, Y: pos.Y}) {
+			v.X = -v.X
+		}
+		if bounce(grid.Point{X: pos.X, Y: pos.Y + v.Y}) {
+			v.Y = -v.Y
+		}
+		for j := 0; j < 4 && bounce(pos.Add(v)); j++ {
+			v.X, v.Y = -v.X, -v.Y
+		}
+
+		pos = pos.Add(v)
+	}
+	return 0, false
+}
+
+// Find returns the position of a tile of type t in the world.
+func (c *Cabinet) Find(t Tile) (grid.Point, bool) {
+	return c.World.Find(func(ct Tile) bool { return ct == t })
+}
+
+func sign(n int64) int64 {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,182 @@
+package arcade
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+// Solver is a joystick which checks each move before making it, by forking the
+// game and playing on until the ball has come back off the paddle. Moves which
+// lose the ball in the forked game are never made, and if no move can keep the
+// ball in play then the solver gives up with an error rather than losing.
+//
+// The guarantee is best-effort: each forked game is played for at most
+// maxLookahead frames. A move whose forked game runs out of frames is only
+// made if no other move is seen to be safe.
+//
+// Moves are tried in the order preferred by a predictor, which also plays the
+// forked games.
+type Solver struct {
+	c    *Cabinet
+	prog *intcode.Prog
+	ai   *Predictor
+
+	Frames int // Number of moves made.
+	Forks  int // Number of forked games played.
+}
+
+// lookaheadCatches is the number of times that the ball must come back off the
+// paddle in a forked game for a move to be considered safe. Checking beyond
+// the first catch means that we don't catch the ball from somewhere that
+// leaves the paddle unable to reach it the next time around.
+const lookaheadCatches = 2
+
+// maxLookahead bounds the frames played by a forked game, in case the ball
+// never comes back down to the paddle.
+const maxLookahead = 1000
+
+var (
+	errSafe    = errors.New("ball came back off the paddle")
+	errLost    = errors.New("ball lost")
+	errUnknown = errors.New("looked too far ahead")
+)
+
+// outcome is what becomes of the ball in a forked game.
+type outcome int
+
+const (
+	lost    outcome = iota
+	unknown         // The game ran out of frames before the ball was caught.
+	safe
+)
+
+func NewSolver(c *Cabinet, prog *intcode.Prog) *Solver {
+	return &Solver{
+		c:    c,
+		prog: prog,
+		ai:   NewPredictor(c),
+	}
+}
+
+func (s *Solver) Position() (int64, error) {
+	s.Frames++
+	best, err := s.ai.Position()
+	if err != nil {
+		return 0, err
+	}
+	moves := []int64{best}
+	for _, move := range []int64{0, -1, 1} {
+		if move != best {
+			moves = append(moves, move)
+		}
+	}
+	guess, guessed := int64(0), false
+	for _, move := range moves {
+		o, err := s.try(move)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case o == safe:
+			return move, nil
+		case o == unknown && !guessed:
+			guess, guessed = move, true
+		}
+	}
+	if guessed {
+		return guess, nil
+	}
+	return 0, fmt.Errorf("no move at frame %d keeps the ball in play", s.Frames)
+}
+
+// try forks the game from its current state and plays on from move, reporting
+// whether the ball is kept in play.
+func (s *Solver) try(move int64) (outcome, error) {
+	s.Forks++
+
+	world := s.c.clone()
+	ai := *s.ai
+	ai.c = world
+	world.Joystick = &lookahead{c: world, ai: &ai, first: &move}
+
+	fork := s.prog.Clone()
+	fork.IO = world
+	fork.Trace = nil
+
+	err := intcode.Exec(fork)
+	switch {
+	case errors.Is(err, errSafe):
+		return safe, nil
+	case errors.Is(err, errLost):
+		return lost, nil
+	case errors.Is(err, errUnknown):
+		return unknown, nil
+	case err != nil:
+		return lost, err
+	}
+
+	// The game is over: did we win?
+	if _, blocks := world.Find(TileBlock); blocks {
+		return lost, nil
+	}
+	return safe, nil
+}
+
+// lookahead is the joystick of a forked game, which makes the first move given
+// and then follows the ai until the ball has either come back up off the
+// paddle (errSafe) or gone past it (errLost), or until it has played
+// maxLookahead frames (errUnknown).
+type lookahead struct {
+	c      *Cabinet
+	ai     *Predictor
+	first  *int64
+	frames int
+	low    bool // Whether the ball is down at the paddle.
+	caught int  // Number of times the ball has come back off the paddle.
+}
+
+func (l *lookahead) Position() (int64, error) {
+	ball, ok := l.c.Find(TileBall)
+	paddle, pok := l.c.Find(TilePaddle)
+	if ok && pok {
+		switch {
+		case ball.Y >= paddle.Y:
+			return 0, errLost
+		case ball.Y == paddle.Y-1:
+			l.low = true
+		case l.low:
+			l.low = false
+			l.caught++
+			if l.caught == lookaheadCatches {
+				return 0, errSafe
+			}
+		}
+	}
+
+	l.frames++
+	if l.frames > maxLookahead {
+		return 0, errUnknown
+	}
+
+	// Keep the ai's idea of the ball's velocity up to date, whichever move
+	// we're making.
+	move, err := l.ai.Position()
+	if l.first != nil {
+		move, l.first = *l.first, nil
+	}
+	return move, err
+}
+
+// clone returns a copy of the world, score and display state of c which can be
+// played on independently of c. The copy has no joystick, isn't drawn, and
+// doesn't emit events.
+func (c *Cabinet) clone() *Cabinet {
+	d := *c
+	d.World = c.World.Clone()
+	d.Draw = nil
+	d.Joystick = nil
+	d.Events = nil
+	return &d
+}
@@ -0,0 +1,66 @@
+package arcade
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+var errStop = errors.New("stopped")
+
+// stopAfter is a joystick which moves as j does for n moves, and then stops the
+// game.
+type stopAfter struct {
+	j Joystick
+	n int
+}
+
+func (s *stopAfter) Position() (int64, error) {
+	if s.n == 0 {
+		return 0, errStop
+	}
+	s.n--
+	return s.j.Position()
+}
+
+// outputs counts the outputs of a game.
+type outputs struct {
+	intcode.IO
+	n int
+}
+
+func (o *outputs) Output(n int64) error {
+	o.n++
+	return o.IO.Output(n)
+}
+
+// The events of a game played by the solver are just those of the game itself,
+// and not of the games it forks to look ahead.
+func TestSolverEvents(t *testing.T) {
+	code, err := intcode.Read("../cmd/arcade/input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code[0] = 2
+
+	var events bytes.Buffer
+	c := New()
+	c.Events = json.NewEncoder(&events)
+	out := &outputs{IO: c}
+	prog := &intcode.Prog{IO: out, Mem: code}
+	solver := NewSolver(c, prog)
+	c.Joystick = &stopAfter{j: solver, n: 20}
+	if err := intcode.Exec(prog); !errors.Is(err, errStop) {
+		t.Fatalf("Exec = %v, expected %v", err, errStop)
+	}
+
+	if solver.Forks == 0 {
+		t.Fatal("the solver didn't look ahead")
+	}
+	if got, want := bytes.Count(events.Bytes(), []byte("\n")), out.n/3; got != want {
+		t.Errorf("%d events for %d outputs, expected %d", got, out.n, want)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"io"
+
+	"github.com/icio/adventofcode2019/fuel"
+)
+
+func init() {
+	register(1, 1, "fuel for the mass of each module", countFuel(fuel.Simple))
+	register(1, 2, "fuel for each module and its fuel", countFuel(fuel.Recursive))
+}
+
+func countFuel(mode fuel.Mode) solver {
+	return func(r io.Reader) (string, error) {
+		stats, err := fuel.CountBig(r, mode)
+		if err != nil {
+			return "", err
+		}
+		return stats.Total.String(), nil
+	}
+}
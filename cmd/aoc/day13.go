@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/icio/adventofcode2019/arcade"
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+func init() {
+	register(13, 1, "blocks on the arcade screen when the game starts", func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		c := arcade.New()
+		if err := intcode.Exec(&intcode.Prog{IO: c, Mem: code}); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(arcade.Count(c.World, arcade.TileBlock), 10), nil
+	})
+	register(13, 2, "score after breaking every block", func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		if len(code) == 0 {
+			return "", fmt.Errorf("empty program")
+		}
+		code[0] = 2 // Play for free.
+		c := arcade.New()
+		c.Joystick = arcade.NewFollower(c)
+		if err := intcode.Exec(&intcode.Prog{IO: c, Mem: code}); err != nil {
+			return "", err
+		}
+		if _, ok := c.Find(arcade.TileBlock); ok {
+			return "", fmt.Errorf("lost the ball with a score of %d", c.Score)
+		}
+		return strconv.FormatInt(c.Score, 10), nil
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+func init() {
+	register(2, 1, "restore the gravity assist program to its 1202 state", func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		n, err := gravityAssist(code, 12, 2)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	})
+	register(2, 2, "find the noun and verb producing 19690720", func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		for noun := int64(0); noun <= 99; noun++ {
+			for verb := int64(0); verb <= 99; verb++ {
+				n, err := gravityAssist(code, noun, verb)
+				if err != nil {
+					return "", err
+				}
+				if n == 19690720 {
+					return strconv.FormatInt(100*noun+verb, 10), nil
+				}
+			}
+		}
+		return "", errors.New("no noun and verb produce 19690720")
+	})
+}
+
+// gravityAssist runs a copy of code with the given noun and verb, returning the
+// value left at position 0. The program is given no input.
+func gravityAssist(code []int64, noun, verb int64) (int64, error) {
+	if len(code) < 3 {
+		return 0, errors.New("program too short for a noun and verb")
+	}
+	p := &intcode.Prog{IO: &listIO{}, Mem: append([]int64(nil), code...)}
+	p.Mem[1], p.Mem[2] = noun, verb
+	if err := intcode.Exec(p); err != nil {
+		return 0, err
+	}
+	return p.Mem[0], nil
+}
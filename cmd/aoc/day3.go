@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/icio/adventofcode2019/wires"
+)
+
+func init() {
+	register(3, 1, "distance to the closest crossing of the wires", func(r io.Reader) (string, error) {
+		ws, err := readWires(r)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(wires.ClosestTaxiAll(0, 0, ws), 10), nil
+	})
+	register(3, 2, "fewest steps along the wires to a crossing", func(r io.Reader) (string, error) {
+		ws, err := readWires(r)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(wires.ClosestStepsAll(wires.Point(0, 0), ws), 10), nil
+	})
+}
+
+// readWires reads the wires from r, all starting from the origin.
+func readWires(r io.Reader) ([][]wires.Rect, error) {
+	ws, err := wires.Read(r, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(ws) < 2 {
+		return nil, fmt.Errorf("expected at least two wires, got %d", len(ws))
+	}
+	return ws, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func init() {
+	register(5, 1, "diagnostic code for the air conditioner", diagnose(1))
+	register(5, 2, "diagnostic code for the thermal radiator controller", diagnose(5))
+}
+
+// diagnose returns the solver running the diagnostic program for system id,
+// whose last output is the diagnostic code and whose others report the result
+// of each test: 0 if it passed.
+func diagnose(id int64) solver {
+	return func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		out, err := execute(code, id)
+		if err != nil {
+			return "", err
+		}
+		if len(out) == 0 {
+			return "", fmt.Errorf("no diagnostic code for system %d", id)
+		}
+		for i, n := range out[:len(out)-1] {
+			if n != 0 {
+				return "", fmt.Errorf("test %d failed with %d", i+1, n)
+			}
+		}
+		return strconv.FormatInt(out[len(out)-1], 10), nil
+	}
+}
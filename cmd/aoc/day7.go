@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+func init() {
+	register(7, 1, "highest signal through the amplifiers in series", amplify(0, series))
+	register(7, 2, "highest signal through the amplifiers in a feedback loop", amplify(5, feedback))
+}
+
+// amplify returns the solver finding the highest signal sent to the thrusters
+// by the amplifiers given each order of the phase settings first, ..., first+4.
+func amplify(first int64, signal func(code, phases []int64) (int64, error)) solver {
+	return func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		var max int64
+		var failed error
+		phases := []int64{first, first + 1, first + 2, first + 3, first + 4}
+		permute(phases, len(phases), func() bool {
+			n, err := signal(code, phases)
+			if err != nil {
+				failed = fmt.Errorf("phases %v: %w", phases, err)
+				return false
+			}
+			if n > max {
+				max = n
+			}
+			return true
+		})
+		if failed != nil {
+			return "", failed
+		}
+		return strconv.FormatInt(max, 10), nil
+	}
+}
+
+// permute calls f with each order of the first n values of s in place, by
+// Heap's algorithm, until f returns false.
+func permute(s []int64, n int, f func() bool) bool {
+	if n <= 1 {
+		return f()
+	}
+	for i := 0; i < n-1; i++ {
+		if !permute(s, n-1, f) {
+			return false
+		}
+		if n%2 == 0 {
+			s[i], s[n-1] = s[n-1], s[i]
+		} else {
+			s[0], s[n-1] = s[n-1], s[0]
+		}
+	}
+	return permute(s, n-1, f)
+}
+
+// series returns the signal out of amplifiers connected one after the other.
+func series(code, phases []int64) (int64, error) {
+	var signal int64
+	for _, phase := range phases {
+		out, err := execute(code, phase, signal)
+		if err != nil {
+			return 0, err
+		}
+		if len(out) != 1 {
+			return 0, fmt.Errorf("expected one output, got %v", out)
+		}
+		signal = out[0]
+	}
+	return signal, nil
+}
+
+// feedback returns the last signal out of amplifiers connected in a loop, each
+// running until it halts.
+func feedback(code, phases []int64) (int64, error) {
+	// pipes[i] carries the signals into amplifier i from the one before it.
+	pipes := make([]chan int64, len(phases))
+	for i, phase := range phases {
+		pipes[i] = make(chan int64, 2)
+		pipes[i] <- phase
+	}
+	pipes[0] <- 0
+
+	// Closing done when an amplifier fails stops the others, rather than
+	// leaving them to wait forever to read from or write to it.
+	done := make(chan struct{})
+	var stop sync.Once
+	errs := make(chan error, len(phases))
+	for i := range phases {
+		out := pipes[(i+1)%len(pipes)]
+		p := &intcode.Prog{IO: chanIO{pipes[i], out, done}, Mem: append([]int64(nil), code...)}
+		go func(i int) {
+			// Closing the output tells the next amplifier that there are no
+			// more signals once this one halts.
+			defer close(out)
+			if err := intcode.Exec(p); err != nil {
+				stop.Do(func() { close(done) })
+				errs <- fmt.Errorf("amplifier %d: %w", i, err)
+				return
+			}
+			errs <- nil
+		}(i)
+	}
+	var failed error
+	for range phases {
+		err := <-errs
+		// Report the failure which stopped the others, over them stopping.
+		if err != nil && (failed == nil || errors.Is(failed, errStopped) && !errors.Is(err, errStopped)) {
+			failed = err
+		}
+	}
+	if failed != nil {
+		return 0, failed
+	}
+
+	// The final signal is left unread in the input of the first amplifier.
+	var signal int64
+	for n := range pipes[0] {
+		signal = n
+	}
+	return signal, nil
+}
+
+// errStopped is returned by chanIO once done is closed.
+var errStopped = errors.New("stopped")
+
+// chanIO reads the inputs of a program from in, and writes its outputs to out,
+// until done is closed.
+type chanIO struct {
+	in   <-chan int64
+	out  chan<- int64
+	done <-chan struct{}
+}
+
+func (c chanIO) Input() (int64, error) {
+	select {
+	case n, ok := <-c.in:
+		if !ok {
+			return 0, fmt.Errorf("input closed")
+		}
+		return n, nil
+	case <-c.done:
+		return 0, errStopped
+	}
+}
+
+func (c chanIO) Output(n int64) error {
+	select {
+	case c.out <- n:
+		return nil
+	case <-c.done:
+		return errStopped
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func init() {
+	register(9, 1, "BOOST keycode in test mode", boost(1))
+	register(9, 2, "coordinates of the distress signal in sensor boost mode", boost(2))
+}
+
+// boost returns the solver running the BOOST program in mode, which outputs
+// just its answer unless any of its opcodes malfunction.
+func boost(mode int64) solver {
+	return func(r io.Reader) (string, error) {
+		code, err := readProgram(r)
+		if err != nil {
+			return "", err
+		}
+		out, err := execute(code, mode)
+		if err != nil {
+			return "", err
+		}
+		if len(out) != 1 {
+			return "", fmt.Errorf("expected one output, got %v", out)
+		}
+		return strconv.FormatInt(out[0], 10), nil
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+// readProgram reads an Intcode program from r.
+func readProgram(r io.Reader) ([]int64, error) {
+	code, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return intcode.Parse(string(code))
+}
+
+// listIO feeds a program each of its inputs in turn, and collects its outputs.
+type listIO struct {
+	in, out []int64
+}
+
+func (l *listIO) Input() (int64, error) {
+	if len(l.in) == 0 {
+		return 0, errors.New("no more input")
+	}
+	n := l.in[0]
+	l.in = l.in[1:]
+	return n, nil
+}
+
+func (l *listIO) Output(n int64) error {
+	l.out = append(l.out, n)
+	return nil
+}
+
+// execute runs a copy of code until it halts, with the given inputs, and
+// returns its outputs.
+func execute(code []int64, inputs ...int64) ([]int64, error) {
+	l := &listIO{in: inputs}
+	err := intcode.Exec(&intcode.Prog{IO: l, Mem: append([]int64(nil), code...)})
+	return l.out, err
+}
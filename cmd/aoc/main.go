@@ -0,0 +1,153 @@
+// Command aoc solves each of the puzzles implemented so far, reading the puzzle
+// input from a file or stdin, as used:
+//
+//	go run ./cmd/aoc/ list
+//	go run ./cmd/aoc/ run 7 2 ./day7part2/input
+//	go run ./cmd/aoc/ run 1 1 < ./cmd/fuel/input
+//
+// Each day registers a solver for each of its parts from an init function in
+// its own file.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// A solver returns the answer to one part of a day's puzzle for the input
+// read from r.
+type solver func(r io.Reader) (string, error)
+
+type puzzle struct {
+	day, part int
+	desc      string
+	solve     solver
+}
+
+var puzzles []puzzle
+
+// register adds the solver for a part of a day's puzzle.
+func register(day, part int, desc string, solve solver) {
+	for _, p := range puzzles {
+		if p.day == day && p.part == part {
+			panic(fmt.Sprintf("aoc: day %d part %d registered twice", day, part))
+		}
+	}
+	puzzles = append(puzzles, puzzle{day, part, desc, solve})
+	sort.Slice(puzzles, func(i, j int) bool {
+		if puzzles[i].day != puzzles[j].day {
+			return puzzles[i].day < puzzles[j].day
+		}
+		return puzzles[i].part < puzzles[j].part
+	})
+}
+
+var commands = []struct {
+	name, desc string
+	run        func(args []string) error
+}{
+	{"run", "solve a part of a day's puzzle: run <day> <part> [input]", run},
+	{"list", "list the puzzles that can be solved", list},
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name != flag.Arg(0) {
+			continue
+		}
+		if err := c.run(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Unrecognised command %q.\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: aoc <command> [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-5s %s\n", c.name, c.desc)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: aoc run <day> <part> [input]")
+		fmt.Fprintln(os.Stderr, "\nReads the puzzle input from the input file, or stdin if none is given.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 || fs.NArg() > 3 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	day, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid day %q", fs.Arg(0))
+	}
+	part, err := strconv.Atoi(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("invalid part %q", fs.Arg(1))
+	}
+	p, ok := find(day, part)
+	if !ok {
+		return fmt.Errorf("day %d part %d isn't solved yet; see aoc list", day, part)
+	}
+
+	in := io.Reader(os.Stdin)
+	if fs.NArg() == 3 {
+		f, err := os.Open(fs.Arg(2))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	answer, err := p.solve(in)
+	if err != nil {
+		return fmt.Errorf("day %d part %d: %w", day, part, err)
+	}
+	fmt.Println(answer)
+	return nil
+}
+
+// find returns the puzzle for a part of a day.
+func find(day, part int) (puzzle, bool) {
+	for _, p := range puzzles {
+		if p.day == day && p.part == part {
+			return p, true
+		}
+	}
+	return puzzle{}, false
+}
+
+func list(args []string) error {
+	if len(args) > 0 {
+		return errors.New("list takes no arguments")
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tPART\tPUZZLE")
+	for _, p := range puzzles {
+		fmt.Fprintf(tw, "%d\t%d\t%s\n", p.day, p.part, p.desc)
+	}
+	return tw.Flush()
+}
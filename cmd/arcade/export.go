@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/icio/adventofcode2019/arcade"
+	"github.com/icio/adventofcode2019/grid"
+)
+
+// paletteText is the index in palette of the colour of text.
+const paletteText = 5
+
+// palette colours the frames, indexed by tile and then the text.
+var palette = color.Palette{
+	arcade.TileEmpty:  color.RGBA{0x10, 0x10, 0x18, 0xff},
+	arcade.TileWall:   color.RGBA{0x90, 0x90, 0x98, 0xff},
+	arcade.TileBlock:  color.RGBA{0xe0, 0xa0, 0x30, 0xff},
+	arcade.TilePaddle: color.RGBA{0x40, 0xc0, 0xe0, 0xff},
+	arcade.TileBall:   color.RGBA{0xf0, 0x40, 0x40, 0xff},
+	paletteText:       color.RGBA{0xff, 0xff, 0xff, 0xff},
+}
+
+// digits is a 3x5 bitmap font of the digits 0-9 and -, one row per line with
+// the most significant bit on the left.
+var digits = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 3, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 2, 2, 2},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'-': {0, 0, 7, 0, 0},
+}
+
+// exporter renders each frame of the world of a cabinet as an image, with the
+// score written beneath the board. Frames are either written as numbered PNG
+// files into dir as they're drawn, or collected into an animated GIF.
+type exporter struct {
+	c     *arcade.Cabinet
+	scale int
+	dir   string // Directory for PNG frames, or "" for a GIF.
+	delay int    // Delay between GIF frames, in 100ths of a second.
+
+	gif  gif.GIF
+	prev *image.Paletted
+	n    int
+	err  error
+}
+
+func newExporter(c *arcade.Cabinet, scale int, dir string, delay int) *exporter {
+	return &exporter{c: c, scale: scale, dir: dir, delay: delay}
+}
+
+// frame renders the current state of the world.
+func (e *exporter) frame() {
+	if e.err != nil {
+		return
+	}
+	img := e.render()
+	e.n++
+
+	if e.dir != "" {
+		e.err = e.writePNG(img)
+		return
+	}
+
+	// Only keep the part of each GIF frame that has changed, leaving the rest
+	// of the previous frame showing beneath it.
+	if e.prev != nil && e.prev.Bounds() == img.Bounds() {
+		changed := diff(e.prev, img)
+		e.prev = img
+		if changed.Empty() {
+			e.gif.Delay[len(e.gif.Delay)-1] += e.delay
+			return
+		}
+		sub := image.NewPaletted(changed, palette)
+		draw.Draw(sub, changed, img, changed.Min, draw.Src)
+		img = sub
+	} else {
+		e.prev = img
+	}
+	e.gif.Image = append(e.gif.Image, img)
+	e.gif.Delay = append(e.gif.Delay, e.delay)
+	e.gif.Disposal = append(e.gif.Disposal, gif.DisposalNone)
+}
+
+// render draws the world and score into a new image.
+func (e *exporter) render() *image.Paletted {
+	c, s := e.c, e.scale
+	min, max := c.World.Bounds()
+	text := (s + 1) / 2 // Size of each pixel of the font.
+	w := int(max.X-min.X+1) * s
+	h := int(max.Y-min.Y+1)*s + 7*text
+	img := image.NewPaletted(image.Rect(0, 0, w, h), palette)
+
+	c.World.Each(func(pt grid.Point, t arcade.Tile) bool {
+		if t >= 0 && int(t) < paletteText {
+			fill(img, int(pt.X-min.X)*s, int(pt.Y-min.Y)*s, s, s, uint8(t))
+		}
+		return true
+	})
+
+	// Score.
+	top := int(max.Y-min.Y+1)*s + text
+	for i, c := range strconv.FormatInt(c.Score, 10) {
+		glyph := digits[c]
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) != 0 {
+					fill(img, text+(i*4+col)*text, top+row*text, text, text, paletteText)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func (e *exporter) writePNG(img image.Image) error {
+	f, err := os.Create(filepath.Join(e.dir, fmt.Sprintf("frame-%05d.png", e.n)))
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// close writes out the GIF, if that's what we're exporting, and returns any
+// error encountered while exporting.
+func (e *exporter) close(w io.Writer) error {
+	if e.err != nil || e.dir != "" {
+		return e.err
+	}
+	return gif.EncodeAll(w, &e.gif)
+}
+
+// export creates an exporter of the frames of c to path: an animated GIF if it
+// ends in .gif, and otherwise a directory of PNGs. The returned func finishes
+// the export.
+func export(c *arcade.Cabinet, path string, scale int) (*exporter, func() error, error) {
+	if scale < 1 {
+		return nil, nil, fmt.Errorf("scale must be positive but got %d", scale)
+	}
+	if filepath.Ext(path) != ".gif" {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, nil, err
+		}
+		e := newExporter(c, scale, path, 0)
+		return e, func() error { return e.close(nil) }, nil
+	}
+
+	e := newExporter(c, scale, "", 8)
+	return e, func() error {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := e.close(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}, nil
+}
+
+func fill(img *image.Paletted, x, y, w, h int, c uint8) {
+	for j := y; j < y+h; j++ {
+		for i := x; i < x+w; i++ {
+			img.SetColorIndex(i, j, c)
+		}
+	}
+}
+
+// diff returns the smallest rectangle containing all the pixels which differ
+// between a and b, which must have the same bounds.
+func diff(a, b *image.Paletted) image.Rectangle {
+	var r image.Rectangle
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if a.ColorIndexAt(x, y) != b.ColorIndexAt(x, y) {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	"github.com/icio/adventofcode2019/arcade"
+)
+
+// errQuit is returned by the keyboard when the player asks to stop playing.
+var errQuit = errors.New("player quit")
+
+// keyboard is an arcade.Joystick controlled by the arrow keys (or a and d) of a
+// terminal. If no key is pressed within a tick then the joystick is left in
+// neutral, or moved by assist when set.
+type keyboard struct {
+	keys   <-chan int64
+	tick   time.Duration
+	assist arcade.Joystick
+}
+
+// newKeyboard starts reading key presses from r, which should be a terminal in
+// raw mode.
+func newKeyboard(r io.Reader, tick time.Duration) *keyboard {
+	keys := make(chan int64, 16)
+	go readKeys(r, keys)
+	return &keyboard{keys: keys, tick: tick}
+}
+
+// Keys as read by readKeys, in addition to the joystick positions.
+const keyQuit = 2
+
+func (k *keyboard) Position() (int64, error) {
+	timeout := time.NewTimer(k.tick)
+	defer timeout.Stop()
+
+	select {
+	case key, open := <-k.keys:
+		// Take the most recent of any keys pressed while we weren't looking
+		// so that held keys don't keep moving the paddle after release.
+	drain:
+		for open && key != keyQuit {
+			select {
+			case key, open = <-k.keys:
+			default:
+				break drain
+			}
+		}
+		if key == keyQuit || !open {
+			return 0, errQuit
+		}
+
+		// Hold the joystick for the rest of the tick, as though nothing had
+		// been pressed any sooner.
+		<-timeout.C
+		return key, nil
+	case <-timeout.C:
+		if k.assist != nil {
+			return k.assist.Position()
+		}
+		return 0, nil
+	}
+}
+
+// readKeys sends the joystick position of each key press read from r to keys,
+// closing keys when r can no longer be read.
+func readKeys(r io.Reader, keys chan<- int64) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		for i := 0; i < n; i++ {
+			switch b := buf[i]; {
+			case b == 'a' || b == 'A' || b == 'h':
+				keys <- -1
+			case b == 'd' || b == 'D' || b == 'l':
+				keys <- 1
+			case b == 's' || b == 'S' || b == ' ':
+				keys <- 0
+			case b == 'q' || b == 'Q' || b == 3: // 3 is ctrl-c.
+				keys <- keyQuit
+			case b == 0x1b && i+2 < n && buf[i+1] == '[':
+				// Arrow keys arrive as escape sequences.
+				switch buf[i+2] {
+				case 'D':
+					keys <- -1
+				case 'C':
+					keys <- 1
+				}
+				i += 2
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// rawTerminal puts the terminal on stdin into raw mode, so that key presses are
+// read immediately and not echoed, returning a func restoring the terminal.
+func rawTerminal() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(state)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := osexec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
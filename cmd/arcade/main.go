@@ -0,0 +1,351 @@
+// Command arcade runs the arcade cabinet of day 13, as used:
+//
+//	go run ./cmd/arcade/ count-blocks ./cmd/arcade/input
+//	go run ./cmd/arcade/ autoplay -ai predict ./cmd/arcade/input
+//	go run ./cmd/arcade/ play -assist -record game.rec ./cmd/arcade/input
+//	go run ./cmd/arcade/ replay -recording game.rec ./cmd/arcade/input
+//	go run ./cmd/arcade/ render -ai solve -o game.gif ./cmd/arcade/input
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/icio/adventofcode2019/arcade"
+	"github.com/icio/adventofcode2019/grid"
+	"github.com/icio/adventofcode2019/intcode"
+)
+
+var commands = []struct {
+	name, desc string
+	run        func(args []string) error
+}{
+	{"count-blocks", "count the blocks on the screen when the game starts", countBlocks},
+	{"play", "play the game with the keyboard", play},
+	{"autoplay", "watch the AI play the game", autoplay},
+	{"render", "write each frame of a game as JSON, PNGs or a GIF", render},
+	{"replay", "watch a recorded game", replay},
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name != flag.Arg(0) {
+			continue
+		}
+		if err := c.run(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Unrecognised command %q.\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: arcade <command> [flags] <intcode>")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", c.name, c.desc)
+	}
+}
+
+// load parses the flags of a command and reads the program named by its
+// argument.
+func load(fs *flag.FlagSet, args []string, free bool) ([]int64, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return program(fs, free)
+}
+
+// program reads the program named by the argument of the parsed flags. From the
+// puzzle instructions: setting instruction 0 to 2 provides repeated play of the
+// game, which is done if free.
+func program(fs *flag.FlagSet, free bool) ([]int64, error) {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return nil, errors.New("expected the path to the intcode program")
+	}
+	code, err := intcode.Read(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	if free {
+		code[0] = 2
+	}
+	return code, nil
+}
+
+func countBlocks(args []string) error {
+	fs := flag.NewFlagSet("count-blocks", flag.ExitOnError)
+	events := fs.Bool("events", false, "count from the JSON events of render on stdin, rather than running the program")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *events {
+		return countEvents(os.Stdin)
+	}
+
+	code, err := program(fs, false)
+	if err != nil {
+		return err
+	}
+	player := arcade.New()
+	if err := intcode.Exec(&intcode.Prog{IO: player, Mem: code}); err != nil {
+		return err
+	}
+
+	fmt.Println(arcade.Count(player.World, arcade.TileBlock))
+	return nil
+}
+
+// countEvents counts the blocks in the JSON events read from r, as they are on
+// the screen when the game starts. The screen is drawn before the first score
+// is shown, so the events of the rest of a game played by render are ignored.
+func countEvents(r io.Reader) error {
+	// Each line of input is an event of the form {"x":..,"y":..,"tile":..}
+	// or {"score":..}.
+	var event struct {
+		X, Y  *int64
+		Tile  *arcade.Tile
+		Score *int64
+	}
+
+	world := grid.New[arcade.Tile]()
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		event.X, event.Y, event.Tile, event.Score = nil, nil, nil, nil
+		err := dec.Decode(&event)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("event %d is invalid: %w", n, err)
+		}
+		if event.Score != nil {
+			break
+		}
+		if event.X == nil || event.Y == nil || event.Tile == nil {
+			return fmt.Errorf("event %d is neither a tile nor a score", n)
+		}
+		world.Set(grid.Point{X: *event.X, Y: *event.Y}, *event.Tile)
+	}
+
+	fmt.Println(arcade.Count(world, arcade.TileBlock))
+	return nil
+}
+
+func play(args []string) error {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	fps := fs.Float64("fps", 12.5, "maximum frames drawn per second, or 0 for unthrottled")
+	tick := fs.Duration("tick", 100*time.Millisecond, "how long to wait for a key press each frame")
+	assist := fs.Bool("assist", false, "let the AI move the joystick whenever no key is pressed")
+	aiName := fs.String("ai", "follow", "the AI to assist with: follow (the ball) or predict (where the ball lands)")
+	record := fs.String("record", "", "record the game to `file`, for replay")
+	code, err := load(fs, args, true)
+	if err != nil {
+		return err
+	}
+
+	player := arcade.New()
+	prog := &intcode.Prog{IO: player, Mem: code}
+	kb := newKeyboard(os.Stdin, *tick)
+	if *assist {
+		kb.assist, err = arcade.NewAI(*aiName, player, prog)
+		if err != nil {
+			return err
+		}
+	}
+	player.Joystick = kb
+
+	restore, err := rawTerminal()
+	if err != nil {
+		return fmt.Errorf("failed to put the terminal in raw mode: %w", err)
+	}
+	err = watch(prog, player, *fps, *record)
+	restore()
+	if errors.Is(err, errQuit) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	fmt.Println(player.Score)
+	return nil
+}
+
+func autoplay(args []string) error {
+	fs := flag.NewFlagSet("autoplay", flag.ExitOnError)
+	fps := fs.Float64("fps", 12.5, "maximum frames drawn per second, or 0 for unthrottled")
+	aiName := fs.String("ai", "follow", "how the AI moves the paddle: follow (the ball), predict (where the ball lands) or solve (looking ahead so as never to lose the ball)")
+	quiet := fs.Bool("quiet", false, "don't draw the game, just report the score")
+	record := fs.String("record", "", "record the game to `file`, for replay")
+	code, err := load(fs, args, true)
+	if err != nil {
+		return err
+	}
+
+	player := arcade.New()
+	prog := &intcode.Prog{IO: player, Mem: append([]int64(nil), code...)}
+	ai, err := arcade.NewAI(*aiName, player, prog)
+	if err != nil {
+		return err
+	}
+	player.Joystick = ai
+	if *quiet {
+		err = run(prog, *record)
+	} else {
+		err = watch(prog, player, *fps, *record)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Compare the joystick moves made by the AI against just following the
+	// ball about.
+	if s, ok := ai.(*arcade.Solver); ok {
+		fmt.Printf("Cleared in %d frames, looking ahead %d times.\n", s.Frames, s.Forks)
+	}
+	if *aiName != "follow" {
+		follow := arcade.New()
+		if err := intcode.Exec(&intcode.Prog{IO: follow, Mem: code}); err != nil {
+			return err
+		}
+		fmt.Printf("%d joystick moves, against %d following the ball.\n", player.Moves, follow.Moves)
+	}
+	fmt.Println(player.Score)
+	return nil
+}
+
+func render(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	aiName := fs.String("ai", "follow", "how the AI moves the paddle: follow, predict or solve")
+	out := fs.String("o", "-", "write the frames to `path`: an animated GIF if it ends .gif, JSON events if -, and otherwise a directory of PNGs")
+	scale := fs.Int("scale", 4, "the size in pixels of each tile of PNGs and GIFs")
+	free := fs.Bool("free", true, "play for free, rather than just drawing the screen and halting")
+	code, err := load(fs, args, false)
+	if err != nil {
+		return err
+	}
+	if *free {
+		code[0] = 2
+	}
+
+	player := arcade.New()
+	prog := &intcode.Prog{IO: player, Mem: code}
+	player.Joystick, err = arcade.NewAI(*aiName, player, prog)
+	if err != nil {
+		return err
+	}
+
+	// Let someone else worry about what the game outputs.
+	if *out == "-" {
+		player.Events = json.NewEncoder(os.Stdout)
+		return intcode.Exec(prog)
+	}
+
+	exp, finish, err := export(player, *out, *scale)
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	player.Draw = exp.frame
+	if err := intcode.Exec(prog); err != nil {
+		return err
+	}
+	exp.frame()
+	if err := finish(); err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	return nil
+}
+
+func replay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	fps := fs.Float64("fps", 12.5, "maximum frames drawn per second, or 0 for unthrottled")
+	recording := fs.String("recording", "", "the `file` recorded by play or autoplay")
+	code, err := load(fs, args, true)
+	if err != nil {
+		return err
+	}
+	if *recording == "" {
+		return errors.New("replay: -recording is required")
+	}
+	f, err := os.Open(*recording)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	player := arcade.New()
+	prog := &intcode.Prog{Mem: code}
+	rp, err := intcode.NewReplayer(f, prog)
+	if err != nil {
+		return err
+	}
+	rp.Out = player
+	prog.IO = replayed{rp, player}
+	if err := watch(prog, player, *fps, ""); err != nil {
+		return err
+	}
+	if err := rp.Done(); err != nil {
+		return err
+	}
+	fmt.Println(player.Score)
+	return nil
+}
+
+// replayed draws the player each time the replay provides input, as the player
+// would had it been providing the input itself.
+type replayed struct {
+	*intcode.Replayer
+	c *arcade.Cabinet
+}
+
+func (r replayed) Input() (int64, error) {
+	if r.c.Draw != nil {
+		r.c.Draw()
+	}
+	return r.Replayer.Input()
+}
+
+// watch runs the game, drawing it to the terminal as it's played.
+func watch(prog *intcode.Prog, player *arcade.Cabinet, fps float64, record string) error {
+	scr := newScreen(os.Stdout, player, fps)
+	player.Draw = scr.draw
+	err := run(prog, record)
+	if err == nil {
+		player.Draw()
+	}
+	scr.close()
+	return err
+}
+
+// run runs the game, recording its inputs and outputs to the file record if
+// given.
+func run(prog *intcode.Prog, record string) error {
+	if record == "" {
+		return intcode.Exec(prog)
+	}
+	f, err := os.Create(record)
+	if err != nil {
+		return err
+	}
+	prog.IO = &intcode.Recorder{IO: prog.IO, W: f, Prog: prog}
+	err = intcode.Exec(prog)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/icio/adventofcode2019/arcade"
+	"github.com/icio/adventofcode2019/grid"
+)
+
+// ANSI escape sequences.
+const (
+	ansiClear      = "\x1b[2J"
+	ansiReset      = "\x1b[0m"
+	ansiClearLine  = "\x1b[K"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+)
+
+// ansiMove returns the escape sequence moving the cursor to the 1-indexed row
+// and column.
+func ansiMove(row, col int64) string {
+	return fmt.Sprintf("\x1b[%d;%dH", row, col)
+}
+
+// colour returns the ANSI escape sequence used to colour the tile.
+func colour(t arcade.Tile) string {
+	switch t {
+	case arcade.TileWall:
+		return "\x1b[37;47m"
+	case arcade.TileBlock:
+		return "\x1b[33m"
+	case arcade.TilePaddle:
+		return "\x1b[1;36m"
+	case arcade.TileBall:
+		return "\x1b[1;31m"
+	default:
+		return ""
+	}
+}
+
+// screen draws the world of a cabinet to an ANSI terminal in place, redrawing
+// only the tiles that have changed since the previous frame.
+type screen struct {
+	w     *bufio.Writer
+	c     *arcade.Cabinet
+	frame time.Duration // Minimum time between frames, or 0 for unthrottled.
+
+	drawn    *grid.Grid[arcade.Tile] // What's on screen.
+	min, max grid.Point              // Bounds of the world on screen.
+	last     time.Time
+}
+
+func newScreen(w io.Writer, c *arcade.Cabinet, fps float64) *screen {
+	s := &screen{
+		w: bufio.NewWriter(w),
+		c: c,
+	}
+	if fps > 0 {
+		s.frame = time.Duration(float64(time.Second) / fps)
+	}
+	return s
+}
+
+// draw updates the terminal to show the current state of the world.
+func (s *screen) draw() {
+	c := s.c
+	min, max := c.World.Bounds()
+
+	// Start afresh whenever the bounds of the world change.
+	if s.drawn == nil || s.min != min || s.max != max {
+		s.drawn = grid.New[arcade.Tile]()
+		s.min, s.max = min, max
+		s.w.WriteString(ansiHideCursor + ansiClear)
+	}
+
+	// Draw the tiles that differ from what's on screen.
+	var blocks int
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			pt := grid.Point{X: x, Y: y}
+			t := c.World.Get(pt)
+			if t == arcade.TileBlock {
+				blocks++
+			}
+			if d, ok := s.drawn.Lookup(pt); ok && d == t {
+				continue
+			}
+			s.drawn.Set(pt, t)
+			s.w.WriteString(ansiMove(y-min.Y+1, x-min.X+1))
+			s.w.WriteString(colour(t) + t.String() + ansiReset)
+		}
+	}
+
+	// Status line.
+	s.w.WriteString(ansiMove(max.Y-min.Y+2, 1) + ansiClearLine)
+	fmt.Fprintf(s.w, "Score: %d  Blocks: %d", c.Score, blocks)
+	s.w.Flush()
+
+	// Throttle to the frame rate.
+	if s.frame > 0 {
+		if d := s.frame - time.Since(s.last); d > 0 {
+			time.Sleep(d)
+		}
+		s.last = time.Now()
+	}
+}
+
+// close leaves the cursor beneath the drawing, ready for further output.
+func (s *screen) close() {
+	s.w.WriteString(ansiMove(s.max.Y-s.min.Y+3, 1) + ansiShowCursor)
+	s.w.Flush()
+}
@@ -0,0 +1,112 @@
+// Command fuel counts the fuel required to launch the modules of day 1, reading
+// the mass of each module from a line of the input file, or stdin if none is
+// given:
+//
+//	go run ./cmd/fuel/ < ./cmd/fuel/input
+//	go run ./cmd/fuel/ -mode recursive -modules ./cmd/fuel/input
+//
+// Masses may be arbitrarily large, but must be non-negative integers. Any
+// malformed line is reported with its line number and the command exits with a
+// non-zero status: immediately by default, or with -strict=false after skipping
+// the line and counting the fuel for the rest.
+//
+// For millions of modules, -workers counts batches of lines in parallel, so
+// long as each mass and the total fuel fit in an int64.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/icio/adventofcode2019/fuel"
+)
+
+func main() {
+	log.SetFlags(0)
+	modeName := flag.String("mode", "simple", "count the fuel for just each module (simple), or also for its fuel (recursive)")
+	modules := flag.Bool("modules", false, "list the fuel required by each module")
+	strict := flag.Bool("strict", true, "stop at the first malformed line, rather than skipping it")
+	workers := flag.Int("workers", 0, "count batches of masses with this many workers, if any")
+	flag.Parse()
+
+	mode, err := fuel.ParseMode(*modeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	in := io.Reader(os.Stdin)
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	default:
+		log.Fatalf("Expected at most one input, got %d.", flag.NArg())
+	}
+
+	if *workers > 0 {
+		if *modules || !*strict {
+			log.Fatal("Can't use -workers with -modules or -strict=false.")
+		}
+		stats, err := fuel.Count(in, mode, *workers)
+		if errors.Is(err, fuel.ErrOverflow) {
+			log.Fatalf("%v; count without -workers for totals of any size.", err)
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d modules requiring %d fuel.\n", stats.Modules, stats.Total)
+		if stats.Modules > 0 {
+			fmt.Printf("Fuel per module: min %d, max %d, mean %.2f.\n", stats.Min, stats.Max, stats.Mean())
+		}
+		return
+	}
+
+	var stats fuel.BigStats
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	if *modules {
+		fmt.Fprintln(tw, "MODULE\tMASS\tFUEL\t")
+	}
+	skipped := 0
+	s := bufio.NewScanner(in)
+	s.Buffer(nil, 1<<24)
+	for line := 1; s.Scan(); line++ {
+		mass, err := fuel.ParseMass(s.Text())
+		if err != nil {
+			if *strict {
+				log.Fatalf("line %d: %v", line, err)
+			}
+			log.Printf("line %d: %v, skipping", line, err)
+			skipped++
+			continue
+		}
+		f := mode.FuelBig(mass)
+		stats.Add(f)
+		if *modules {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t\n", stats.Modules, mass, f)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if *modules {
+		tw.Flush()
+		fmt.Println()
+	}
+
+	fmt.Printf("%d modules requiring %s fuel.\n", stats.Modules, &stats.Total)
+	if stats.Modules > 0 {
+		fmt.Printf("Fuel per module: min %s, max %s, mean %s.\n", &stats.Min, &stats.Max, stats.Mean().FloatString(2))
+	}
+	if skipped > 0 {
+		log.Fatalf("Skipped %d malformed lines.", skipped)
+	}
+}
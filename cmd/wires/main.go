@@ -0,0 +1,259 @@
+// Command wires analyses the crossed wires of day 3, as used:
+//
+//	go run ./cmd/wires/ report ./day3part1/input
+//	go run ./cmd/wires/ report -json -k 2 < wires.txt
+//	go run ./cmd/wires/ closest -metric euclidean -k 5 ./day3part1/input
+//	go run ./cmd/wires/ render -by steps -o wires.svg ./day3part1/input
+//	echo -e 'R8,U5,L5,D3\nU7,R6,D4,L4' | go run ./cmd/wires/ draw
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/icio/adventofcode2019/wires"
+)
+
+var commands = []struct {
+	name, desc string
+	run        func(args []string) error
+}{
+	{"report", "report where the wires cross themselves and each other", report},
+	{"closest", "rank the closest crossings by a metric", closest},
+	{"render", "draw the wires and their crossings as SVG", render},
+	{"draw", "draw small wires as text, as in the puzzle", draw},
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name != flag.Arg(0) {
+			continue
+		}
+		if err := c.run(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Unrecognised command %q.\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: wires <command> [flags] [input]")
+	fmt.Fprintln(os.Stderr, "\nReads one wire per line from the input file, or stdin if none is given.")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.desc)
+	}
+}
+
+// load parses the flags of a command and reads the wires named by its
+// argument, all starting from the origin.
+func load(fs *flag.FlagSet, args []string) ([][]wires.Rect, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	in := io.Reader(os.Stdin)
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	default:
+		fs.Usage()
+		return nil, fmt.Errorf("expected at most one input, got %d", fs.NArg())
+	}
+	return wires.Read(in, 0, 0)
+}
+
+func report(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	k := fs.Int("k", 3, "list the points crossed by at least `k` wires")
+	asJSON := fs.Bool("json", false, "write the report as JSON")
+	verbose := fs.Bool("v", false, "list every crossing of each wire and pair")
+	ws, err := load(fs, args)
+	if err != nil {
+		return err
+	}
+
+	r := wires.Analyse(wires.Point(0, 0), ws, *k)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "WIRE\tMOVES\tSTEPS\tSELF-CROSSINGS")
+	for i, w := range r.Wires {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\n", i, w.Moves, w.Steps, len(w.SelfCrossings))
+		if !*verbose {
+			continue
+		}
+		for _, c := range w.SelfCrossings {
+			fmt.Fprintf(tw, "  %s\t\t%d\t%d\n", area(c.At), c.First, c.Again)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	fmt.Fprintln(tw, "WIRES\tCROSSINGS\tCLOSEST TAXI\tCLOSEST STEPS")
+	for _, p := range r.Pairs {
+		fmt.Fprintf(tw, "%d-%d\t%d\t%s\t%s\n", p.A, p.B, len(p.Crossings), dist(p.ClosestTaxi), dist(p.ClosestSteps))
+		if !*verbose {
+			continue
+		}
+		for _, c := range p.Crossings {
+			fmt.Fprintf(tw, "  %s\t\t%d\t%d\n", area(c.At), c.Taxi, c.Steps)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d points crossed by %d or more wires.\n", len(r.Junctions), r.K)
+	for _, j := range r.Junctions {
+		fmt.Printf("  %d,%d: %v\n", j.X, j.Y, j.Wires)
+	}
+	return nil
+}
+
+func closest(args []string) error {
+	fs := flag.NewFlagSet("closest", flag.ExitOnError)
+	metric := fs.String("metric", "taxi", "how to measure each crossing: "+metricNames())
+	k := fs.Int("k", 10, "list the closest `k` crossings, or all of them if 0")
+	asJSON := fs.Bool("json", false, "write the crossings as JSON")
+	ws, err := load(fs, args)
+	if err != nil {
+		return err
+	}
+	m, ok := wires.MetricNamed(*metric)
+	if !ok {
+		var names []string
+		for _, m := range wires.Metrics {
+			names = append(names, m.Name)
+		}
+		return fmt.Errorf("closest: unknown -metric %q, expected one of %s", *metric, strings.Join(names, ", "))
+	}
+
+	rs := wires.Rank(wires.Point(0, 0), ws, m, *k)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rs)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "RANK\tWIRES\tAT\t%s\n", strings.ToUpper(m.Name))
+	for i, r := range rs {
+		fmt.Fprintf(tw, "%d\t%d-%d\t%s\t%.6g\n", i+1, r.A, r.B, area(r.At), r.Distance)
+	}
+	return tw.Flush()
+}
+
+// metricNames lists the names of the metrics.
+func metricNames() string {
+	var names []string
+	for _, m := range wires.Metrics {
+		names = append(names, m.Name+" ("+m.Desc+")")
+	}
+	return strings.Join(names, ", ")
+}
+
+func render(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	out := fs.String("o", "-", "write the SVG to `file`, or stdout if -")
+	width := fs.Int("width", 800, "the width of the canvas in pixels")
+	height := fs.Int("height", 800, "the height of the canvas in pixels")
+	by := fs.String("by", "taxi", "highlight the crossing closest by taxi (distance from the origin) or by steps (along both wires)")
+	ws, err := load(fs, args)
+	if err != nil {
+		return err
+	}
+	if *by != "taxi" && *by != "steps" {
+		return fmt.Errorf("render: unknown -by %q, expected taxi or steps", *by)
+	}
+
+	orig := wires.Point(0, 0)
+	r := wires.Analyse(orig, ws, 2)
+	s := newSVG(*width, *height, orig, ws, r)
+	best := int64(-1)
+	for _, p := range r.Pairs {
+		for _, c := range p.Crossings {
+			d, at := c.Taxi, c.TaxiAt
+			if *by == "steps" {
+				d, at = c.Steps, c.StepsAt
+			}
+			if best < 0 || d < best {
+				best = d
+				s.best = at
+				s.bestLabel = fmt.Sprintf("%d,%d: %d %s, wires %d and %d", at.X1, at.Y1, d, *by, p.A, p.B)
+			}
+		}
+	}
+
+	if *out == "-" {
+		return s.write(os.Stdout)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := s.write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func draw(args []string) error {
+	fs := flag.NewFlagSet("draw", flag.ExitOnError)
+	maxCells := fs.Int64("max", 1<<20, "refuse to draw more than `n` cells")
+	ws, err := load(fs, args)
+	if err != nil {
+		return err
+	}
+
+	orig := wires.Point(0, 0)
+	min, max := bounds(orig, ws)
+	if w, h := max.X1-min.X1+3, max.Y1-min.Y1+3; w*h > *maxCells {
+		return fmt.Errorf("draw: the wires are too large to draw as %dx%d cells; try render", w, h)
+	}
+	return wires.Draw(os.Stdout, orig, ws)
+}
+
+// dist formats d, or - if there is no distance.
+func dist(d int64) string {
+	if d < 0 {
+		return "-"
+	}
+	return fmt.Sprint(d)
+}
+
+// area formats r as a point, if it is one, or otherwise as its corners.
+func area(r wires.Rect) string {
+	a, b := r.Corners()
+	if a == b {
+		return fmt.Sprintf("%d,%d", a.X1, a.Y1)
+	}
+	return fmt.Sprintf("%d,%d-%d,%d", a.X1, a.Y1, b.X1, b.Y1)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/icio/adventofcode2019/wires"
+)
+
+// palette colours each wire in turn.
+var palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#9467bd", "#8c564b", "#e377c2", "#17becf", "#bcbd22"}
+
+// svg draws the wires, started from orig, onto a canvas of width by height
+// pixels. Each crossing is marked, and the best is highlighted.
+type svg struct {
+	width, height int
+	orig          wires.Rect
+	wires         [][]wires.Rect
+	report        *wires.Report
+	best          wires.Rect // The winning crossing.
+	bestLabel     string
+
+	// The transformation from wire coordinates to the canvas.
+	minX, minY int64
+	scale      float64
+	pad        float64
+}
+
+func newSVG(width, height int, orig wires.Rect, ws [][]wires.Rect, r *wires.Report) *svg {
+	s := &svg{width: width, height: height, orig: orig, wires: ws, report: r, pad: 10}
+
+	min, max := bounds(orig, ws)
+	s.minX, s.minY = min.X1, min.Y1
+	s.scale = 1
+	if dx, dy := max.X1-min.X1, max.Y1-min.Y1; dx > 0 || dy > 0 {
+		sx := (float64(width) - 2*s.pad) / float64(maxInt(dx, 1))
+		sy := (float64(height) - 2*s.pad) / float64(maxInt(dy, 1))
+		s.scale = sx
+		if sy < sx {
+			s.scale = sy
+		}
+	}
+	return s
+}
+
+// x and y transform a position to the canvas.
+func (s *svg) x(x int64) float64 { return s.pad + float64(x-s.minX)*s.scale }
+func (s *svg) y(y int64) float64 { return s.pad + float64(y-s.minY)*s.scale }
+
+func (s *svg) write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %[1]d %[2]d">`+"\n", s.width, s.height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+
+	for i, w := range s.wires {
+		fmt.Fprintf(bw, `<polyline fill="none" stroke="%s" stroke-width="1" points="%.1f,%.1f`, palette[i%len(palette)], s.x(s.orig.X1), s.y(s.orig.Y1))
+		for _, e := range wires.Ends(s.orig, w) {
+			fmt.Fprintf(bw, " %.1f,%.1f", s.x(e.X1), s.y(e.Y1))
+		}
+		fmt.Fprintf(bw, `"><title>wire %d</title></polyline>`+"\n", i)
+	}
+
+	for _, p := range s.report.Pairs {
+		for _, c := range p.Crossings {
+			a, b := c.At.Corners()
+			if a == b {
+				fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="2" fill="black"><title>%d,%d: wires %d and %d</title></circle>`+"\n",
+					s.x(a.X1), s.y(a.Y1), a.X1, a.Y1, p.A, p.B)
+				continue
+			}
+			fmt.Fprintf(bw, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black" stroke-width="3"><title>%s: wires %d and %d</title></line>`+"\n",
+				s.x(a.X1), s.y(a.Y1), s.x(b.X1), s.y(b.Y1), area(c.At), p.A, p.B)
+		}
+	}
+
+	fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="4" fill="black"><title>origin</title></circle>`+"\n", s.x(s.orig.X1), s.y(s.orig.Y1))
+	if s.bestLabel != "" {
+		fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="6" fill="none" stroke="red" stroke-width="2"><title>%s</title></circle>`+"\n",
+			s.x(s.best.X1), s.y(s.best.Y1), s.bestLabel)
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// bounds returns the top-left and bottom-right points covered by the wires and
+// their origin.
+func bounds(orig wires.Rect, ws [][]wires.Rect) (min, max wires.Rect) {
+	min, max = orig, orig
+	for _, w := range ws {
+		for _, r := range w {
+			min = wires.Point(minInt(min.X1, r.X1), minInt(min.Y1, r.Y1))
+			max = wires.Point(maxInt(max.X1, r.X2), maxInt(max.Y1, r.Y2))
+		}
+	}
+	return min, max
+}
+
+func minInt(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// asciiio provides input and output for programs speaking ASCII. Each line read
+// from r is given to the program as its character codes followed by 10 (a
+// newline). Output codes below 128 are written to w as text, and larger values
+// (which can't be characters) as numbers on their own line.
+//
+// In raw mode every output is written as a number on its own line, which is
+// useful for seeing exactly what a program produces.
+type asciiio struct {
+	r   *bufio.Reader
+	w   *bufio.Writer
+	raw bool
+
+	line []int64 // Remaining codes of the current input line.
+	last byte    // Last byte written to w.
+}
+
+func newASCIIIO(r io.Reader, w io.Writer, raw bool) *asciiio {
+	return &asciiio{
+		r:    bufio.NewReader(r),
+		w:    bufio.NewWriter(w),
+		raw:  raw,
+		last: '\n',
+	}
+}
+
+func (a *asciiio) Input() (int64, error) {
+	if len(a.line) == 0 {
+		// Show everything written so far before waiting on the user.
+		if err := a.w.Flush(); err != nil {
+			return 0, err
+		}
+
+		s, err := a.r.ReadString('\n')
+		if err == io.EOF && s == "" {
+			return 0, io.EOF
+		} else if err != nil && err != io.EOF {
+			return 0, err
+		}
+		s = strings.TrimRight(s, "\r\n")
+		for i := 0; i < len(s); i++ {
+			a.line = append(a.line, int64(s[i]))
+		}
+		a.line = append(a.line, 10)
+	}
+
+	v := a.line[0]
+	a.line = a.line[1:]
+	return v, nil
+}
+
+func (a *asciiio) Output(n int64) error {
+	if !a.raw && n >= 0 && n < 128 {
+		a.write(byte(n))
+		return nil
+	}
+
+	// Numbers get a line of their own.
+	if a.last != '\n' {
+		a.write('\n')
+	}
+	a.w.WriteString(strconv.FormatInt(n, 10))
+	a.write('\n')
+	return nil
+}
+
+func (a *asciiio) write(b byte) {
+	a.w.WriteByte(b)
+	a.last = b
+}
+
+// Close flushes any remaining output.
+func (a *asciiio) Close() error {
+	return a.w.Flush()
+}
@@ -1,70 +1,133 @@
 package main
 
 import (
-	"errors"
+	"flag"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+
+	"github.com/icio/adventofcode2019/intcode"
 )
 
 func main() {
+	record := flag.String("record", "", "record each input and output to `file`")
+	replay := flag.String("replay", "", "replay inputs from a recorded `file`, verifying the outputs before writing them as usual")
+	in := flag.String("in", "", "comma-separated `values` to use as input, in place of prompting")
+	inFile := flag.String("in-file", "", "read input values from `file` (or - for stdin), in place of prompting")
+	out := flag.String("out", "", "write outputs in `format`: lines, csv, tuple or json, reading input from stdin without prompting unless -in or -in-file is given")
+	tuple := flag.Int("tuple", 3, "number of values per line for -out tuple")
+	ascii := flag.Bool("ascii", false, "converse with the program in ASCII text over stdin and stdout")
+	raw := flag.Bool("raw", false, "with -ascii, write every output as a number rather than text")
+	flag.Parse()
+
 	// Read the code.
-	code, err := ioutil.ReadFile(os.Args[1])
+	code, err := intcode.Read(flag.Arg(0))
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	// Parse the code into operators.
-	codeop := strings.Split(strings.TrimSpace(string(code)), ",")
-	intcode := make([]int64, len(codeop))
-	for i, op := range codeop {
-		n, err := strconv.ParseInt(op, 10, 64)
-		if err != nil {
-			log.Fatalf("Failed to parse int %q at position %d.", op, i)
+	p := &intcode.Prog{
+		IO:    stdio{},
+		Mem:   code,
+		Trace: os.Stderr,
+	}
+
+	// closeOut terminates and flushes the output, if it needs it.
+	var closeOut func() error
+
+	// Converse in text.
+	if *ascii {
+		if *in != "" || *inFile != "" || *out != "" {
+			log.Fatalln("-ascii can't be used with -in, -in-file or -out.")
 		}
-		intcode[i] = n
+		a := newASCIIIO(os.Stdin, os.Stdout, *raw)
+		closeOut = a.Close
+		p.IO = a
 	}
 
-	err = exec(&prog{
-		io:  stdio{},
-		mem: intcode,
-	})
-	if err != nil {
-		log.Fatal(err)
+	// Take input and write output non-interactively.
+	if *in != "" || *inFile != "" || *out != "" {
+		pio := pipeio{outputter: stdio{}}
+		switch {
+		case *in != "" && *inFile != "":
+			log.Fatalln("Only one of -in and -in-file may be given.")
+		case *in != "":
+			l, err := parseListInput(*in)
+			if err != nil {
+				log.Fatalf("Bad -in: %s", err)
+			}
+			pio.inputter = l
+		case *inFile != "" && *inFile != "-":
+			f, err := os.Open(*inFile)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			defer f.Close()
+			pio.inputter = newReaderInput(f)
+		default:
+			// Read stdin for -in-file -, and also for -out alone, where
+			// prompting would corrupt the formatted output.
+			pio.inputter = newReaderInput(os.Stdin)
+		}
+		if *out != "" {
+			fo, err := newFormatOutput(os.Stdout, *out, *tuple)
+			if err != nil {
+				log.Fatalf("Bad -out: %s", err)
+			}
+			closeOut = fo.Close
+			pio.outputter = fo
+		}
+		p.IO = pio
 	}
-}
 
-type prog struct {
-	io interface {
-		Input() (int64, error)
-		Output(int64) error
+	// Replay a previous recording in place of the input, writing the outputs
+	// as they would otherwise be.
+	var rp *intcode.Replayer
+	if *replay != "" {
+		if *in != "" || *inFile != "" {
+			log.Fatalln("-replay can't be used with -in or -in-file.")
+		}
+		f, err := os.Open(*replay)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer f.Close()
+		rp, err = intcode.NewReplayer(f, p)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		rp.Out = p.IO
+		p.IO = rp
 	}
-	mem  []int64
-	base int
-}
 
-func (p *prog) get(r int) int64 {
-	if r > len(p.mem) {
-		return 0
+	// Record the inputs and outputs as they pass through.
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer f.Close()
+		p.IO = &intcode.Recorder{IO: p.IO, W: f, Prog: p}
 	}
-	return p.mem[int(r)]
-}
 
-func (p *prog) set(r int, v int64) {
-	if len(p.mem) <= r {
-		c := cap(p.mem)
-		for c < r {
-			c *= 2
+	err = intcode.Exec(p)
+
+	// Close the output even if the program failed, so that what it wrote
+	// isn't lost.
+	if closeOut != nil {
+		if cerr := closeOut(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if rp != nil {
+		if err := rp.Done(); err != nil {
+			log.Fatal(err)
 		}
-		m := p.mem
-		p.mem = make([]int64, c)
-		copy(p.mem, m)
 	}
-	p.mem[r] = v
 }
 
 type stdio struct{}
@@ -91,224 +154,3 @@ func (stdio) Output(n int64) error {
 	_, err := fmt.Println(n)
 	return err
 }
-
-func exec(p *prog) error {
-	opn := 0
-	for opn < len(p.mem) {
-		op := p.mem[opn]
-		switch op % 100 {
-		case 99:
-			// Return.
-			fmt.Fprintf(os.Stderr, "% 4d: ret(99)\n", opn)
-			return nil
-		case 1:
-			// Add.
-			a, b, ans, err := readParamParamAddr(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("add(1): %s", err)
-			}
-			vc := a.v + b.v
-			p.set(ans, vc)
-			fmt.Fprintf(os.Stderr, "% 4d: add(1): %s + %s = %d -> *%d\n", opn, a, b, vc, ans)
-			opn += 4
-		case 2:
-			// Multiply.
-			a, b, ans, err := readParamParamAddr(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("mul(2): %s", err)
-			}
-			vc := a.v * b.v
-			p.set(ans, vc)
-			fmt.Fprintf(os.Stderr, "% 4d: mul(2): %s + %s = %d -> *%d\n", opn, a, b, vc, ans)
-			opn += 4
-		case 3:
-			// Input.
-			dst, err := readAddr(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("inp(3): %s", err)
-			}
-			v, err := p.io.Input()
-			if err != nil {
-				return fmt.Errorf("inp(3): reading input: %w", err)
-			}
-			p.set(dst, v)
-			fmt.Fprintf(os.Stderr, "% 4d: inp(3): %d -> *%d\n", opn, v, dst)
-			opn += 2
-		case 4:
-			// Output.
-			src, err := readParam(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("out(4): %s", err)
-			}
-			fmt.Fprintf(os.Stderr, "% 4d: out(4): %s\n", opn, src)
-			err = p.io.Output(src.v)
-			if err != nil {
-				return fmt.Errorf("out(4): writing output: %w", err)
-			}
-			opn += 2
-		case 5:
-			// Jump-if-True.
-			cond, jump, err := readParamParam(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("jtr(5): %s", err)
-			}
-			if cond.v != 0 {
-				// True.
-				fmt.Fprintf(os.Stderr, "% 4d: jtr(5): %s != 0 => %s\n", opn, cond, jump)
-				opn = int(jump.v)
-			} else {
-				fmt.Fprintf(os.Stderr, "% 4d: jtr(5): %s == 0\n", opn, cond)
-				opn += 3
-			}
-		case 6:
-			// Jump-if-False.
-			cond, jump, err := readParamParam(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("jfa(6): %s", err)
-			}
-			if cond.v == 0 {
-				// False.
-				fmt.Fprintf(os.Stderr, "% 4d: jfa(6): %s == 0 => %s\n", opn, cond, jump)
-				opn = int(jump.v)
-			} else {
-				fmt.Fprintf(os.Stderr, "% 4d: jfa(6): %s != 0\n", opn, cond)
-				opn += 3
-			}
-		case 7:
-			// Less than.
-			a, b, ans, err := readParamParamAddr(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("les(7): %s", err)
-			}
-			var v int64
-			if a.v < b.v {
-				v = 1
-			}
-			p.set(ans, v)
-			fmt.Fprintf(os.Stderr, "% 4d: les(7): %s < %s = %d -> *%d\n", opn, a, b, v, ans)
-			opn += 4
-		case 8:
-			// Equals.
-			a, b, ans, err := readParamParamAddr(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("equ(8): %s", err)
-			}
-			var v int64
-			if a.v == b.v {
-				v = 1
-			}
-			p.set(ans, v)
-			fmt.Fprintf(os.Stderr, "% 4d: equ(8): %s == %s = %d -> *%d\n", opn, a, b, v, ans)
-			opn += 4
-		case 9:
-			// Base.
-			base, err := readParam(p, opn, 1)
-			if err != nil {
-				return fmt.Errorf("bas(9): %s", err)
-			}
-			b := p.base
-			p.base += int(base.v)
-			fmt.Fprintf(os.Stderr, "% 4d: bas(9): %d + %s ~> %d\n", opn, b, base, p.base)
-			opn += 2
-		default:
-			return fmt.Errorf("intcode: unrecognised op %d at position %d", op%100, opn)
-		}
-	}
-	return errors.New("intcode: no operation")
-}
-
-type param struct {
-	f int
-	p int
-	r int
-	v int64
-}
-
-func (p param) String() string {
-	switch p.f {
-	case flagLit:
-		return strconv.FormatInt(p.v, 10)
-	case flagPos:
-		return "(*" + strconv.Itoa(p.p) + " -> " + strconv.FormatInt(p.v, 10) + ")"
-	case flagRel:
-		return "(*" + strconv.Itoa(p.p) + "-" + strconv.Itoa(p.r) + " -> " + strconv.FormatInt(p.v, 10) + ")"
-	}
-	return fmt.Sprintf("%#v", p)
-}
-
-func readParamParamAddr(pr *prog, opn int, n int) (a param, b param, addr int, err error) {
-	a, b, err = readParamParam(pr, opn, n)
-	if err != nil {
-		return
-	}
-	addr, err = readAddr(pr, opn, n+2)
-	return
-}
-
-func readParamParam(pr *prog, opn int, n int) (a param, b param, err error) {
-	a, err = readParam(pr, opn, n)
-	if err != nil {
-		return
-	}
-	b, err = readParam(pr, opn, n+1)
-	return
-}
-
-func readParam(pr *prog, opn int, n int) (param, error) {
-	f := readFlag(pr, opn, n)
-	var p, r int
-	switch f {
-	case flagLit:
-		return param{f: f, p: -1, v: pr.get(opn + n)}, nil
-	case flagPos:
-		p = int(pr.get(opn + n))
-	case flagRel:
-		r = int(pr.get(opn + n))
-		p = pr.base + r
-	}
-	return param{f: f, p: p, r: r, v: pr.get(p)}, nil
-}
-
-func readAddr(pr *prog, opn int, n int) (int, error) {
-	f := readFlag(pr, opn, n)
-	switch f {
-	case flagLit:
-		return -1, fmt.Errorf("wanted pointer but literal at position %d", opn+n)
-	case flagPos:
-		return int(pr.get(opn + n)), nil
-	case flagRel:
-		return pr.base + int(pr.get(opn+n)), nil
-	default:
-		return -1, fmt.Errorf("unrecognised flag %d", f)
-	}
-}
-
-func readFlag(pr *prog, opn int, n int) int {
-	return int((pr.get(opn) / exp10(n+1)) % 10)
-}
-
-const (
-	flagPos = 0 // Positional mode: the value is at the address
-	flagLit = 1 // Immediate mode: the value is literal
-	flagRel = 2 // Relative mode: the value is at the address relative to the root
-)
-
-func exp10(n int) int64 {
-	switch n {
-	case 0:
-		return 1e0
-	case 1:
-		return 1e1
-	case 2:
-		return 1e2
-	case 3:
-		return 1e3
-	case 4:
-		return 1e4
-	}
-	v := int64(1)
-	for ; n > 0; n-- {
-		v *= 10
-	}
-	return v
-}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type inputter interface {
+	Input() (int64, error)
+}
+
+type outputter interface {
+	Output(int64) error
+}
+
+// pipeio combines a separate input and output into an intcode.IO.
+type pipeio struct {
+	inputter
+	outputter
+}
+
+// listInput provides each of the values in turn, and then io.EOF.
+type listInput []int64
+
+func parseListInput(s string) (*listInput, error) {
+	vs, err := parseInts(strings.NewReader(s))
+	l := listInput(vs)
+	return &l, err
+}
+
+func (l *listInput) Input() (int64, error) {
+	if len(*l) == 0 {
+		return 0, io.EOF
+	}
+	v := (*l)[0]
+	*l = (*l)[1:]
+	return v, nil
+}
+
+// readerInput reads integers separated by whitespace or commas from a reader,
+// without prompting.
+type readerInput struct {
+	s *bufio.Scanner
+}
+
+func newReaderInput(r io.Reader) *readerInput {
+	s := bufio.NewScanner(r)
+	s.Split(scanInts)
+	return &readerInput{s}
+}
+
+func (r *readerInput) Input() (int64, error) {
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+	v, err := strconv.ParseInt(r.s.Text(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad input %q: %w", r.s.Text(), err)
+	}
+	return v, nil
+}
+
+// parseInts reads all of the integers separated by whitespace or commas from r.
+func parseInts(r io.Reader) ([]int64, error) {
+	in := newReaderInput(r)
+	var vs []int64
+	for {
+		v, err := in.Input()
+		if err == io.EOF {
+			return vs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+}
+
+// scanInts is a bufio.SplitFunc splitting on runs of whitespace and commas.
+func scanInts(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	isSep := func(b byte) bool {
+		return b == ',' || b == ' ' || b == '\t' || b == '\n' || b == '\r'
+	}
+
+	// Skip leading separators.
+	start := 0
+	for start < len(data) && isSep(data[start]) {
+		start++
+	}
+
+	// Find the end of the token.
+	for i := start; i < len(data); i++ {
+		if isSep(data[i]) {
+			return i + 1, data[start:i], nil
+		}
+	}
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+// Output formats.
+const (
+	outLines = "lines" // One value per line.
+	outCSV   = "csv"   // Comma-separated values on a single line.
+	outTuple = "tuple" // Groups of n space-separated values per line.
+	outJSON  = "json"  // A JSON array of values.
+)
+
+// formatOutput writes outputs to w in one of the output formats. Close must be
+// called once the program has finished to terminate the output.
+type formatOutput struct {
+	w      *bufio.Writer
+	format string
+	n      int // Values per line, for outTuple.
+	count  int
+}
+
+func newFormatOutput(w io.Writer, format string, n int) (*formatOutput, error) {
+	switch format {
+	case outLines, outCSV, outJSON:
+	case outTuple:
+		if n < 1 {
+			return nil, fmt.Errorf("tuple size must be positive but got %d", n)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognised output format %q", format)
+	}
+	return &formatOutput{w: bufio.NewWriter(w), format: format, n: n}, nil
+}
+
+func (o *formatOutput) Output(v int64) error {
+	switch o.format {
+	case outCSV:
+		if o.count > 0 {
+			o.w.WriteByte(',')
+		}
+	case outTuple:
+		if o.count%o.n != 0 {
+			o.w.WriteByte(' ')
+		}
+	case outJSON:
+		if o.count == 0 {
+			o.w.WriteByte('[')
+		} else {
+			o.w.WriteByte(',')
+		}
+	}
+	o.count++
+	o.w.WriteString(strconv.FormatInt(v, 10))
+
+	// Flush complete lines so that anything reading from us sees them before
+	// we block waiting on our next input.
+	if o.format == outLines || (o.format == outTuple && o.count%o.n == 0) {
+		o.w.WriteByte('\n')
+		return o.w.Flush()
+	}
+	return nil
+}
+
+// Close terminates the output and flushes it to the underlying writer.
+func (o *formatOutput) Close() error {
+	switch o.format {
+	case outCSV:
+		if o.count > 0 {
+			o.w.WriteByte('\n')
+		}
+	case outTuple:
+		if o.count%o.n != 0 {
+			o.w.WriteByte('\n')
+		}
+	case outJSON:
+		if o.count == 0 {
+			o.w.WriteString("[")
+		}
+		o.w.WriteString("]\n")
+	}
+	return o.w.Flush()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanInts(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+	}{
+		{"", nil},
+		{" ,\n", nil},
+		{"1", []string{"1"}},
+		{"1,2, 3\r\n\t-4,,5\n", []string{"1", "2", "3", "-4", "5"}},
+		{",x,", []string{"x"}},
+	} {
+		s := bufio.NewScanner(strings.NewReader(test.in))
+		s.Split(scanInts)
+		var got []string
+		for s.Scan() {
+			got = append(got, s.Text())
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("scanInts(%q) = %q, expected %q", test.in, got, test.exp)
+		}
+	}
+}
+
+func TestParseListInput(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []int64
+		err string
+	}{
+		{"", nil, ""},
+		{"1,-2, 3", []int64{1, -2, 3}, ""},
+		{"1,two", nil, `bad input "two": strconv.ParseInt: parsing "two": invalid syntax`},
+	} {
+		l, err := parseListInput(test.in)
+		if got := fmt.Sprint(err); (err != nil || test.err != "") && got != test.err {
+			t.Errorf("parseListInput(%q) error %q, expected %q", test.in, got, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		var got []int64
+		for {
+			v, err := l.Input()
+			if err != nil {
+				break
+			}
+			got = append(got, v)
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("parseListInput(%q) gave %v, expected %v", test.in, got, test.exp)
+		}
+	}
+}
+
+func TestFormatOutput(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		n      int
+		vs     []int64
+		exp    string
+	}{
+		{outLines, 0, nil, ""},
+		{outLines, 0, []int64{1, -2}, "1\n-2\n"},
+		{outCSV, 0, nil, ""},
+		{outCSV, 0, []int64{1, -2, 3}, "1,-2,3\n"},
+		{outTuple, 2, []int64{1, 2, 3, 4}, "1 2\n3 4\n"},
+		{outTuple, 3, []int64{1, 2, 3, 4}, "1 2 3\n4\n"},
+		{outJSON, 0, nil, "[]\n"},
+		{outJSON, 0, []int64{1, -2}, "[1,-2]\n"},
+	} {
+		var b strings.Builder
+		o, err := newFormatOutput(&b, test.format, test.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range test.vs {
+			if err := o.Output(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := o.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != test.exp {
+			t.Errorf("%s output of %v = %q, expected %q", test.format, test.vs, b.String(), test.exp)
+		}
+	}
+
+	for _, test := range []struct {
+		format string
+		n      int
+		err    string
+	}{
+		{outTuple, 0, "tuple size must be positive but got 0"},
+		{"xml", 0, `unrecognised output format "xml"`},
+	} {
+		if _, err := newFormatOutput(&strings.Builder{}, test.format, test.n); fmt.Sprint(err) != test.err {
+			t.Errorf("newFormatOutput(%q, %d) error %v, expected %s", test.format, test.n, err, test.err)
+		}
+	}
+}
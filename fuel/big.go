@@ -0,0 +1,110 @@
+package fuel
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+var (
+	bigTwo   = big.NewInt(2)
+	bigThree = big.NewInt(3)
+)
+
+// ForMassBig is ForMass for masses of any size.
+func ForMassBig(mass *big.Int) *big.Int {
+	f := new(big.Int).Quo(mass, bigThree)
+	return f.Sub(f, bigTwo)
+}
+
+// ForModuleBig is ForModule for masses of any size.
+func ForModuleBig(mass *big.Int) *big.Int {
+	if mass.IsInt64() {
+		return big.NewInt(ForModule(mass.Int64()))
+	}
+	fuel := new(big.Int)
+	for f := ForMassBig(mass); f.Sign() >= 0; f = ForMassBig(f) {
+		fuel.Add(fuel, f)
+	}
+	return fuel
+}
+
+// FuelBig is Fuel for masses of any size.
+func (m Mode) FuelBig(mass *big.Int) *big.Int {
+	if m == Recursive {
+		return ForModuleBig(mass)
+	}
+	return ForMassBig(mass)
+}
+
+// ParseMass parses the mass of a module: a non-negative integer of any size,
+// optionally surrounded by spaces.
+func ParseMass(s string) (*big.Int, error) {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return nil, errors.New("missing mass")
+	}
+	// SetString would also accept prefixes such as 0x and underscores.
+	for _, c := range t {
+		if (c < '0' || c > '9') && c != '-' && c != '+' {
+			return nil, fmt.Errorf("invalid mass %q", t)
+		}
+	}
+	mass, ok := new(big.Int).SetString(t, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid mass %q", t)
+	}
+	if mass.Sign() < 0 {
+		return nil, fmt.Errorf("negative mass %s", mass)
+	}
+	return mass, nil
+}
+
+// BigStats is Stats for fuel of any size.
+type BigStats struct {
+	Modules  int64
+	Total    big.Int
+	Min, Max big.Int
+}
+
+// Add counts the fuel for another module.
+func (s *BigStats) Add(fuel *big.Int) {
+	if s.Modules == 0 || fuel.Cmp(&s.Min) < 0 {
+		s.Min.Set(fuel)
+	}
+	if s.Modules == 0 || fuel.Cmp(&s.Max) > 0 {
+		s.Max.Set(fuel)
+	}
+	s.Modules++
+	s.Total.Add(&s.Total, fuel)
+}
+
+// Mean returns the mean fuel required per module.
+func (s *BigStats) Mean() *big.Rat {
+	if s.Modules == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(&s.Total, big.NewInt(s.Modules))
+}
+
+// CountBig counts the fuel for the mass on each line of r, like Count but for
+// masses and totals of any size. It stops at the first malformed line.
+func CountBig(r io.Reader, mode Mode) (*BigStats, error) {
+	var stats BigStats
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for line := 1; s.Scan(); line++ {
+		mass, err := ParseMass(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("fuel: line %d: %w", line, err)
+		}
+		stats.Add(mode.FuelBig(mass))
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,157 @@
+package fuel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// batchSize is the number of bytes of input counted in each batch.
+const batchSize = 64 << 10
+
+// Count counts the fuel for the mass on each line of r, handing batches of
+// lines to workers goroutines to count in parallel. Unlike ParseMass it needs
+// each mass to fit in an int64, and returns ErrOverflow if the total fuel
+// doesn't. It returns the error of the first malformed line, if any.
+func Count(r io.Reader, mode Mode, workers int) (Stats, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	type batch struct {
+		line int
+		data []byte
+	}
+	type result struct {
+		stats Stats
+		line  int
+		err   error
+	}
+	batches := make(chan batch, workers)
+	results := make(chan result, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				var res result
+				res.line, res.err = count(&res.stats, mode, b.line, b.data)
+				results <- res
+			}
+		}()
+	}
+	read := make(chan error, 1)
+	go func() {
+		read <- split(r, batchSize, func(line int, data []byte) {
+			batches <- batch{line, data}
+		})
+		close(batches)
+		wg.Wait()
+		close(results)
+	}()
+
+	var stats Stats
+	var err error
+	var line int
+	for res := range results {
+		if res.err != nil {
+			if err == nil || res.line < line {
+				err, line = res.err, res.line
+			}
+			continue
+		}
+		// An overflow has no line of its own, so any malformed line is
+		// reported in preference to it.
+		if merr := stats.Merge(res.stats); merr != nil && err == nil {
+			err, line = fmt.Errorf("fuel: %w", merr), math.MaxInt
+		}
+	}
+	if rerr := <-read; rerr != nil {
+		return Stats{}, rerr
+	}
+	if err != nil {
+		return Stats{}, err
+	}
+	return stats, nil
+}
+
+// split reads r in chunks of about size bytes, calling f with each run of
+// whole lines and the number of its first line.
+func split(r io.Reader, size int, f func(line int, data []byte)) error {
+	line := 1
+	var rest []byte
+	for {
+		buf := make([]byte, len(rest)+size)
+		copy(buf, rest)
+		n, err := io.ReadFull(r, buf[len(rest):])
+		buf = buf[:len(rest)+n]
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if len(buf) > 0 {
+				f(line, buf)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("fuel: %w", err)
+		}
+		i := bytes.LastIndexByte(buf, '\n') + 1
+		if i == 0 {
+			rest = buf // The line continues into the next chunk.
+			continue
+		}
+		f(line, buf[:i])
+		line += bytes.Count(buf[:i], []byte{'\n'})
+		rest = buf[i:]
+	}
+}
+
+// count adds the fuel for the mass on each line of data, starting at the given
+// line, to stats. It returns the line and error of the first malformed line.
+func count(stats *Stats, mode Mode, line int, data []byte) (int, error) {
+	for ; len(data) > 0; line++ {
+		text := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			text, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		mass, ok := parseInt(bytes.TrimSpace(text))
+		if !ok {
+			// Fall back to ParseMass for the signs it allows, and its errors.
+			m, err := ParseMass(string(text))
+			if err == nil && !m.IsInt64() {
+				err = fmt.Errorf("mass %s is too large", m)
+			}
+			if err != nil {
+				return line, fmt.Errorf("fuel: line %d: %w", line, err)
+			}
+			mass = m.Int64()
+		}
+		if err := stats.Add(mode.Fuel(mass)); err != nil {
+			return line, fmt.Errorf("fuel: line %d: %w", line, err)
+		}
+	}
+	return line, nil
+}
+
+// parseInt parses the digits b, without the allocation of converting them to a
+// string for strconv.
+func parseInt(b []byte) (int64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	var n int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := int64(c - '0')
+		if n > (math.MaxInt64-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	return n, true
+}
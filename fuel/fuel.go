@@ -0,0 +1,144 @@
+// Package fuel counts the fuel required to launch the modules of day 1.
+package fuel
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ForMass returns the fuel required to launch mass, ignoring the mass of the
+// fuel itself.
+func ForMass(mass int64) int64 {
+	return mass/3 - 2
+}
+
+// ForModule returns the fuel required to launch a module of the given mass,
+// along with the fuel for its fuel, and so on until the fuel required is
+// negative.
+//
+// The rounding at each step rules out a closed form, but each step divides the
+// mass by 3, so it takes only a few steps for any mass to reach the table of
+// small masses.
+func ForModule(mass int64) (fuel int64) {
+	for mass >= int64(len(modules)) {
+		mass = ForMass(mass)
+		fuel += mass
+	}
+	if mass < 0 {
+		return fuel
+	}
+	return fuel + modules[mass]
+}
+
+// modules holds ForModule of each mass small enough to index it.
+var modules = func() []int64 {
+	t := make([]int64, 1<<16)
+	for m := range t {
+		if f := ForMass(int64(m)); f >= 0 {
+			t[m] = f + t[f]
+		}
+	}
+	return t
+}()
+
+// Mode is a way of counting the fuel for a module.
+type Mode int
+
+const (
+	Simple    Mode = iota // ForMass, as in part 1.
+	Recursive             // ForModule, as in part 2.
+)
+
+var modeNames = []string{
+	Simple:    "simple",
+	Recursive: "recursive",
+}
+
+// ParseMode returns the mode called name.
+func ParseMode(name string) (Mode, error) {
+	for m, n := range modeNames {
+		if n == name {
+			return Mode(m), nil
+		}
+	}
+	return 0, fmt.Errorf("fuel: unknown mode %q, expected simple or recursive", name)
+}
+
+func (m Mode) String() string {
+	if m >= 0 && int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// Fuel returns the fuel required for a module of the given mass.
+func (m Mode) Fuel(mass int64) int64 {
+	if m == Recursive {
+		return ForModule(mass)
+	}
+	return ForMass(mass)
+}
+
+// ErrOverflow is returned when the total fuel is too large for Stats, which
+// BigStats can count instead.
+var ErrOverflow = errors.New("total fuel is too large for an int64")
+
+// Stats summarises the fuel required by each of a number of modules.
+type Stats struct {
+	Modules  int64
+	Total    int64
+	Min, Max int64
+}
+
+// Add counts the fuel for another module, or returns ErrOverflow, leaving s
+// unchanged, if the total would overflow.
+func (s *Stats) Add(fuel int64) error {
+	total, ok := add(s.Total, fuel)
+	if !ok {
+		return ErrOverflow
+	}
+	if s.Modules == 0 || fuel < s.Min {
+		s.Min = fuel
+	}
+	if s.Modules == 0 || fuel > s.Max {
+		s.Max = fuel
+	}
+	s.Modules++
+	s.Total = total
+	return nil
+}
+
+// Merge counts the modules counted by t, or returns ErrOverflow, leaving s
+// unchanged, if the total would overflow.
+func (s *Stats) Merge(t Stats) error {
+	if t.Modules == 0 {
+		return nil
+	}
+	total, ok := add(s.Total, t.Total)
+	if !ok {
+		return ErrOverflow
+	}
+	if s.Modules == 0 || t.Min < s.Min {
+		s.Min = t.Min
+	}
+	if s.Modules == 0 || t.Max > s.Max {
+		s.Max = t.Max
+	}
+	s.Modules += t.Modules
+	s.Total = total
+	return nil
+}
+
+// add returns a+b, or false if it overflows.
+func add(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+// Mean returns the mean fuel required per module.
+func (s Stats) Mean() float64 {
+	if s.Modules == 0 {
+		return 0
+	}
+	return float64(s.Total) / float64(s.Modules)
+}
@@ -0,0 +1,248 @@
+package fuel
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// loop is ForModule as it was first written, to check the faster one against.
+func loop(mass int64) (f int64) {
+	for m := ForMass(mass); m >= 0; m = ForMass(m) {
+		f += m
+	}
+	return f
+}
+
+// anyMagnitude generates masses of every magnitude, either side of the table
+// of small masses, for quick.Check.
+func anyMagnitude(vs []reflect.Value, rng *rand.Rand) {
+	for i := range vs {
+		vs[i] = reflect.ValueOf(randomMass(rng))
+	}
+}
+
+func randomMass(rng *rand.Rand) int64 {
+	return rng.Int63() >> uint(rng.Intn(63))
+}
+
+func TestFuel(t *testing.T) {
+	for _, test := range []struct{ mass, simple, recursive int64 }{
+		{12, 2, 2},
+		{14, 2, 2},
+		{1969, 654, 966},
+		{100756, 33583, 50346},
+	} {
+		if f := ForMass(test.mass); f != test.simple {
+			t.Errorf("ForMass(%d) = %d, expected %d", test.mass, f, test.simple)
+		}
+		if f := ForModule(test.mass); f != test.recursive {
+			t.Errorf("ForModule(%d) = %d, expected %d", test.mass, f, test.recursive)
+		}
+	}
+}
+
+func TestForModuleMatchesLoop(t *testing.T) {
+	for _, m := range []int64{-1, 0, 8, 9, 1<<16 - 1, 1 << 16, math.MaxInt64} {
+		if f, l := ForModule(m), loop(m); f != l {
+			t.Errorf("ForModule(%d) = %d, expected %d", m, f, l)
+		}
+	}
+	matches := func(m int64) bool { return ForModule(m) == loop(m) }
+	if err := quick.Check(matches, &quick.Config{MaxCount: 100000, Values: anyMagnitude}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFuelBig(t *testing.T) {
+	for _, mode := range []Mode{Simple, Recursive} {
+		matches := func(m int64) bool {
+			b := mode.FuelBig(big.NewInt(m))
+			return b.IsInt64() && b.Int64() == mode.Fuel(m)
+		}
+		if err := quick.Check(matches, &quick.Config{MaxCount: 10000, Values: anyMagnitude}); err != nil {
+			t.Errorf("%s: %v", mode, err)
+		}
+	}
+}
+
+func TestParseMass(t *testing.T) {
+	for _, test := range []struct {
+		in, err string
+	}{
+		{"", "missing mass"},
+		{"  ", "missing mass"},
+		{"abc", `invalid mass "abc"`},
+		{"0x10", `invalid mass "0x10"`},
+		{"-5", "negative mass -5"},
+		{" 12\r", ""},
+		{"+12", ""},
+		{"123456789012345678901234567890", ""},
+	} {
+		_, err := ParseMass(test.in)
+		if got := fmt.Sprint(err); (err != nil || test.err != "") && got != test.err {
+			t.Errorf("ParseMass(%q) error %q, expected %q", test.in, got, test.err)
+		}
+	}
+}
+
+// Each batch of input is counted the same whatever the number of workers and
+// however the lines end, and the first malformed line is reported.
+func TestCount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := rng.Intn(100000)
+		var in strings.Builder
+		var want Stats
+		for j := 0; j < n; j++ {
+			m := randomMass(rng) >> 17 // Small enough for the total to fit.
+			if err := want.Add(loop(m)); err != nil {
+				t.Fatal(err)
+			}
+			in.WriteString(strconv.FormatInt(m, 10))
+			if rng.Intn(2) == 0 {
+				in.WriteString("\r")
+			}
+			if j < n-1 || rng.Intn(2) == 0 {
+				in.WriteString("\n")
+			}
+		}
+		workers := rng.Intn(8) + 1
+		got, err := Count(strings.NewReader(in.String()), Recursive, workers)
+		if err != nil || got != want {
+			t.Errorf("Count of %d masses with %d workers = %+v, %v, expected %+v", n, workers, got, err, want)
+		}
+
+		if n == 0 {
+			continue
+		}
+		lines := strings.SplitAfter(in.String(), "\n")
+		bad := rng.Intn(n)
+		lines[bad] = "x\n"
+		_, err = Count(strings.NewReader(strings.Join(lines, "")), Recursive, workers)
+		if want := fmt.Sprintf(`fuel: line %d: invalid mass "x"`, bad+1); fmt.Sprint(err) != want {
+			t.Errorf("Count of %d masses with %d workers, error %v, expected %s", n, workers, err, want)
+		}
+	}
+}
+
+func TestCountOverflow(t *testing.T) {
+	in := strings.Repeat("9000000000000000000\n", 4)
+	for _, mode := range []Mode{Simple, Recursive} {
+		for _, workers := range []int{1, 2} {
+			stats, err := Count(strings.NewReader(in), mode, workers)
+			if !errors.Is(err, ErrOverflow) {
+				t.Errorf("%s Count with %d workers = %+v, %v, expected %v", mode, workers, stats, err, ErrOverflow)
+			}
+		}
+	}
+
+	// Batches which don't overflow by themselves can overflow when merged.
+	batch := "6000000000000000000\n" + strings.Repeat("9\n", batchSize/2)
+	stats, err := Count(strings.NewReader(strings.Repeat(batch, 5)), Simple, 4)
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Count of batches = %+v, %v, expected %v", stats, err, ErrOverflow)
+	}
+}
+
+func TestCountBig(t *testing.T) {
+	in := strings.Repeat("9000000000000000000\n", 4)
+	stats, err := CountBig(strings.NewReader(in), Simple)
+	if want := "11999999999999999992"; err != nil || stats.Total.String() != want {
+		t.Errorf("CountBig = %+v, %v, expected a total of %s", stats, err, want)
+	}
+
+	_, err = CountBig(strings.NewReader("12\n14\nx\n"), Simple)
+	if want := `fuel: line 3: invalid mass "x"`; fmt.Sprint(err) != want {
+		t.Errorf("CountBig error %v, expected %s", err, want)
+	}
+}
+
+func TestStatsOverflow(t *testing.T) {
+	var s Stats
+	if err := s.Add(math.MaxInt64); err != nil {
+		t.Fatal(err)
+	}
+	want := s
+	if err := s.Add(1); !errors.Is(err, ErrOverflow) || s != want {
+		t.Errorf("Add past MaxInt64 = %+v, %v, expected %+v, %v", s, err, want, ErrOverflow)
+	}
+	if err := s.Merge(want); !errors.Is(err, ErrOverflow) || s != want {
+		t.Errorf("Merge past MaxInt64 = %+v, %v, expected %+v, %v", s, err, want, ErrOverflow)
+	}
+	if err := s.Add(-2); err != nil || s.Total != math.MaxInt64-2 {
+		t.Errorf("Add(-2) = %+v, %v", s, err)
+	}
+}
+
+// benchMasses returns a million masses like those of the puzzle, one per line.
+func benchMasses() ([]int64, []byte) {
+	const n = 1000000
+	rng := rand.New(rand.NewSource(n))
+	masses := make([]int64, n)
+	var in bytes.Buffer
+	for i := range masses {
+		masses[i] = rng.Int63n(150000-50000) + 50000
+		in.WriteString(strconv.FormatInt(masses[i], 10))
+		in.WriteByte('\n')
+	}
+	return masses, in.Bytes()
+}
+
+func BenchmarkLoop(b *testing.B) {
+	masses, _ := benchMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, m := range masses {
+			loop(m)
+		}
+	}
+}
+
+func BenchmarkForModule(b *testing.B) {
+	masses, _ := benchMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, m := range masses {
+			ForModule(m)
+		}
+	}
+}
+
+func BenchmarkParseMass(b *testing.B) {
+	_, in := benchMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var s BigStats
+		lines := bufio.NewScanner(bytes.NewReader(in))
+		for lines.Scan() {
+			m, err := ParseMass(lines.Text())
+			if err != nil {
+				b.Fatal(err)
+			}
+			s.Add(Recursive.FuelBig(m))
+		}
+	}
+}
+
+func BenchmarkCount(b *testing.B) {
+	_, in := benchMasses()
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(strconv.Itoa(workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Count(bytes.NewReader(in), Recursive, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
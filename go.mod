@@ -1,3 +1,3 @@
 module github.com/icio/adventofcode2019
 
-go 1.13
+go 1.18
@@ -0,0 +1,225 @@
+// Package grid provides a sparse two-dimensional grid of cells, as drawn by
+// the arcade and traced by the wires.
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Point is a position on a grid, with x increasing to the right and y
+// increasing downwards.
+type Point struct{ X, Y int64 }
+
+// Add returns the point offset from p by q.
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Offsets to the neighbours of a point.
+var (
+	Orthogonal = []Point{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	Adjacent   = []Point{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+)
+
+// Grid is a sparse grid of cells of type T. Cells which haven't been set read
+// as the zero value of T.
+//
+// The bounds of the grid grow to include every cell that is set, and aren't
+// shrunk when cells are deleted.
+type Grid[T any] struct {
+	cells    map[Point]T
+	min, max Point
+	bounded  bool // Whether min and max have been set.
+}
+
+// New returns an empty grid.
+func New[T any]() *Grid[T] {
+	return &Grid[T]{cells: make(map[Point]T)}
+}
+
+// Get returns the cell at p.
+func (g *Grid[T]) Get(p Point) T {
+	return g.cells[p]
+}
+
+// Lookup returns the cell at p and whether it has been set.
+func (g *Grid[T]) Lookup(p Point) (T, bool) {
+	v, ok := g.cells[p]
+	return v, ok
+}
+
+// Set sets the cell at p to v.
+func (g *Grid[T]) Set(p Point, v T) {
+	if !g.bounded {
+		g.min, g.max, g.bounded = p, p, true
+	}
+	if p.X < g.min.X {
+		g.min.X = p.X
+	}
+	if p.X > g.max.X {
+		g.max.X = p.X
+	}
+	if p.Y < g.min.Y {
+		g.min.Y = p.Y
+	}
+	if p.Y > g.max.Y {
+		g.max.Y = p.Y
+	}
+	g.cells[p] = v
+}
+
+// Delete unsets the cell at p.
+func (g *Grid[T]) Delete(p Point) {
+	delete(g.cells, p)
+}
+
+// Len returns the number of cells set.
+func (g *Grid[T]) Len() int {
+	return len(g.cells)
+}
+
+// Bounds returns the top-left and bottom-right corners of the grid, inclusive.
+// The bounds of an empty grid are both the origin.
+func (g *Grid[T]) Bounds() (min, max Point) {
+	return g.min, g.max
+}
+
+// Clone returns a copy of the grid which can be changed independently of g.
+func (g *Grid[T]) Clone() *Grid[T] {
+	c := &Grid[T]{
+		cells:   make(map[Point]T, len(g.cells)),
+		min:     g.min,
+		max:     g.max,
+		bounded: g.bounded,
+	}
+	for p, v := range g.cells {
+		c.cells[p] = v
+	}
+	return c
+}
+
+// Each calls f with each cell that is set, in row order (top to bottom, and
+// left to right along each row), until f returns false.
+func (g *Grid[T]) Each(f func(Point, T) bool) {
+	// Scan the whole area of dense grids, rather than sorting their cells.
+	if g.dense() {
+		for y := g.min.Y; y <= g.max.Y; y++ {
+			for x := g.min.X; x <= g.max.X; x++ {
+				p := Point{x, y}
+				if v, ok := g.cells[p]; ok && !f(p, v) {
+					return
+				}
+			}
+		}
+		return
+	}
+
+	ps := make([]Point, 0, len(g.cells))
+	for p := range g.cells {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Y != ps[j].Y {
+			return ps[i].Y < ps[j].Y
+		}
+		return ps[i].X < ps[j].X
+	})
+	for _, p := range ps {
+		if !f(p, g.cells[p]) {
+			return
+		}
+	}
+}
+
+// dense returns whether the cells of the grid cover at least a quarter of the
+// area within its bounds.
+func (g *Grid[T]) dense() bool {
+	// The width and height less one, which fit in a uint64 however far apart
+	// the cells are.
+	w1 := uint64(g.max.X - g.min.X)
+	h1 := uint64(g.max.Y - g.min.Y)
+	n := uint64(4 * len(g.cells))
+	if w1 >= n || h1 >= n {
+		return false
+	}
+	return h1+1 <= n/(w1+1)
+}
+
+// Find returns the first point in row order whose cell satisfies f.
+func (g *Grid[T]) Find(f func(T) bool) (Point, bool) {
+	var found Point
+	var ok bool
+	g.Each(func(p Point, v T) bool {
+		if f(v) {
+			found, ok = p, true
+		}
+		return !ok
+	})
+	return found, ok
+}
+
+// Neighbours returns the points offset from p by each of offsets (such as
+// Orthogonal or Adjacent) which have been set.
+func (g *Grid[T]) Neighbours(p Point, offsets []Point) []Point {
+	var ns []Point
+	for _, o := range offsets {
+		n := p.Add(o)
+		if _, ok := g.cells[n]; ok {
+			ns = append(ns, n)
+		}
+	}
+	return ns
+}
+
+// Format writes the grid to w as text, one line per row within its bounds,
+// using format to write each cell (set or not). Nothing is written for a grid
+// in which no cell has been set.
+func (g *Grid[T]) Format(w io.Writer, format func(T) string) error {
+	if !g.bounded {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	for y := g.min.Y; y <= g.max.Y; y++ {
+		for x := g.min.X; x <= g.max.X; x++ {
+			bw.WriteString(format(g.cells[Point{x, y}]))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// String returns the grid as text, formatting each cell with fmt.Sprint.
+func (g *Grid[T]) String() string {
+	var b strings.Builder
+	g.Format(&b, func(v T) string { return fmt.Sprint(v) })
+	return b.String()
+}
+
+// Parse reads a grid from r, one row per line and one cell per rune, with the
+// top-left rune at the origin. Cells for which parse returns ok as false are
+// left unset.
+func Parse[T any](r io.Reader, parse func(rune) (v T, ok bool, err error)) (*Grid[T], error) {
+	g := New[T]()
+	s := bufio.NewScanner(r)
+	for y := int64(0); s.Scan(); y++ {
+		x := int64(0)
+		for _, c := range s.Text() {
+			v, ok, err := parse(c)
+			if err != nil {
+				return nil, fmt.Errorf("grid: line %d, column %d: %w", y+1, x+1, err)
+			}
+			if ok {
+				g.Set(Point{x, y}, v)
+			}
+			x++
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("grid: %w", err)
+	}
+	return g, nil
+}
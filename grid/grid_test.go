@@ -0,0 +1,181 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEach(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		points []Point
+	}{
+		{"empty", nil},
+		// Scanned across the bounds of the grid.
+		{"dense", []Point{
+			{0, 0}, {1, 0}, {2, 0},
+			{0, 1}, {2, 1},
+			{0, 2}, {1, 2}, {2, 2},
+		}},
+		// Sorted.
+		{"sparse", []Point{
+			{100, -50},
+			{-3, 0}, {7, 0},
+			{-100, 50}, {0, 50},
+		}},
+		// Too far apart for the area of the bounds to fit in an int64.
+		{"far", []Point{
+			{0, 0},
+			{1<<32 - 1, 1<<32 - 1},
+		}},
+		{"farthest", []Point{
+			{math.MinInt64, math.MinInt64},
+			{math.MaxInt64, math.MaxInt64},
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// Set the cells in a shuffled order.
+			g := New[int]()
+			for _, i := range rand.New(rand.NewSource(1)).Perm(len(test.points)) {
+				g.Set(test.points[i], i)
+			}
+
+			var got []Point
+			g.Each(func(p Point, v int) bool {
+				if test.points[v] != p {
+					t.Errorf("cell %v has value %d, expected it at %v", p, v, test.points[v])
+				}
+				got = append(got, p)
+				return true
+			})
+			if !reflect.DeepEqual(got, test.points) {
+				t.Errorf("Each visited %v, expected %v", got, test.points)
+			}
+
+			// Stop when asked.
+			n, want := 0, len(test.points)
+			if want > 2 {
+				want = 2
+			}
+			g.Each(func(Point, int) bool {
+				n++
+				return n < 2
+			})
+			if n != want {
+				t.Errorf("Each visited %d cells, expected to stop after %d", n, want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	g := New[byte]()
+	g.Set(Point{5, 1}, 'x')
+	g.Set(Point{2, 1}, 'x')
+	g.Set(Point{9, 0}, 'y')
+	if p, ok := g.Find(func(c byte) bool { return c == 'x' }); !ok || p != (Point{2, 1}) {
+		t.Errorf("Find(x) = %v, %t, expected {2 1}, true", p, ok)
+	}
+	if p, ok := g.Find(func(c byte) bool { return c == 'z' }); ok {
+		t.Errorf("Find(z) = %v, %t, expected false", p, ok)
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	g := New[bool]()
+	for _, p := range []Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {3, 3}} {
+		g.Set(p, true)
+	}
+	g.Delete(Point{1, 0})
+	if n := g.Len(); n != 4 {
+		t.Errorf("Len() = %d, expected 4", n)
+	}
+	if got, want := g.Neighbours(Point{0, 0}, Orthogonal), []Point{{0, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbours(Orthogonal) = %v, expected %v", got, want)
+	}
+	if got, want := g.Neighbours(Point{0, 0}, Adjacent), []Point{{0, 1}, {1, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbours(Adjacent) = %v, expected %v", got, want)
+	}
+}
+
+// parseWalls parses # as a set cell and . as an unset one.
+func parseWalls(c rune) (bool, bool, error) {
+	switch c {
+	case '#':
+		return true, true, nil
+	case '.':
+		return false, false, nil
+	}
+	return false, false, fmt.Errorf("unexpected %q", c)
+}
+
+func formatWalls(wall bool) string {
+	if wall {
+		return "#"
+	}
+	return "."
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"#\n",
+		"#..\n.#.\n..#\n",
+		"#...#\n.....\n..#..\n",
+		"..#\n#..\n",
+	} {
+		g, err := Parse(strings.NewReader(text), parseWalls)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", text, err)
+			continue
+		}
+		var b strings.Builder
+		if err := g.Format(&b, formatWalls); err != nil {
+			t.Errorf("Format error: %v", err)
+		}
+		if b.String() != text {
+			t.Errorf("Parse(%q) formats as %q", text, b.String())
+		}
+	}
+
+	// Rows and columns without a set cell are outside the bounds.
+	g, err := Parse(strings.NewReader("....\n.#..\n..#.\n"), parseWalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := g.Format(&b, formatWalls); err != nil {
+		t.Fatal(err)
+	}
+	if want := "#.\n.#\n"; b.String() != want {
+		t.Errorf("Format = %q, expected %q", b.String(), want)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse(strings.NewReader("#.\n.x\n"), parseWalls)
+	if want := `grid: line 2, column 2: unexpected 'x'`; fmt.Sprint(err) != want {
+		t.Errorf("Parse error %v, expected %s", err, want)
+	}
+
+	errBad := errors.New("bad")
+	_, err = Parse(strings.NewReader("?"), func(rune) (int, bool, error) { return 0, false, errBad })
+	if !errors.Is(err, errBad) {
+		t.Errorf("Parse error %v doesn't wrap %v", err, errBad)
+	}
+}
+
+func TestFormatEmpty(t *testing.T) {
+	var b strings.Builder
+	if err := New[int]().Format(&b, func(int) string { return "x" }); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "" {
+		t.Errorf("Format of an empty grid = %q, expected nothing", b.String())
+	}
+}
@@ -0,0 +1,366 @@
+// Package intcode runs Intcode programs.
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// IO provides the input to and receives the output of a program.
+type IO interface {
+	Input() (int64, error)
+	Output(int64) error
+}
+
+// Prog is an Intcode program and the state of its execution.
+type Prog struct {
+	IO  IO
+	Mem []int64
+
+	// Trace receives a line for each operation executed, if set.
+	Trace io.Writer
+
+	base int
+
+	// pc is the position at which Exec starts, and is set to the position of
+	// the current instruction whenever the program waits for input.
+	pc int
+
+	// steps counts the instructions executed so far.
+	steps int64
+}
+
+// Read reads the program in the file at path.
+func Read(path string) ([]int64, error) {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(code))
+}
+
+// Parse parses the comma-separated operators of a program.
+func Parse(code string) ([]int64, error) {
+	codeop := strings.Split(strings.TrimSpace(code), ",")
+	intcode := make([]int64, len(codeop))
+	for i, op := range codeop {
+		n, err := strconv.ParseInt(op, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("intcode: failed to parse int %q at position %d", op, i)
+		}
+		intcode[i] = n
+	}
+	return intcode, nil
+}
+
+// Steps returns the number of instructions executed so far.
+func (p *Prog) Steps() int64 {
+	return p.steps
+}
+
+// Clone returns a copy of the program with its own memory, so that it can be
+// run on from its current state independently of p.
+func (p *Prog) Clone() *Prog {
+	c := *p
+	c.Mem = append([]int64(nil), p.Mem...)
+	return &c
+}
+
+func (p *Prog) tracef(format string, a ...interface{}) {
+	if p.Trace != nil {
+		fmt.Fprintf(p.Trace, format, a...)
+	}
+}
+
+// get returns the value at address r. Memory beyond the program reads as 0.
+func (p *Prog) get(r int) (int64, error) {
+	if r < 0 {
+		return 0, fmt.Errorf("negative address %d", r)
+	}
+	if r >= len(p.Mem) {
+		return 0, nil
+	}
+	return p.Mem[r], nil
+}
+
+// set sets the value at address r, growing memory to fit it.
+func (p *Prog) set(r int, v int64) error {
+	if r < 0 {
+		return fmt.Errorf("negative address %d", r)
+	}
+	if len(p.Mem) <= r {
+		c := cap(p.Mem)
+		if c == 0 {
+			c = 1
+		}
+		for c <= r {
+			c *= 2
+		}
+		m := p.Mem
+		p.Mem = make([]int64, c)
+		copy(p.Mem, m)
+	}
+	p.Mem[r] = v
+	return nil
+}
+
+// Exec runs the program until it halts, or until its IO returns an error.
+func Exec(p *Prog) error {
+	opn := p.pc
+	for opn < len(p.Mem) {
+		if opn < 0 {
+			return fmt.Errorf("intcode: jump to negative position %d", opn)
+		}
+		p.steps++
+		op := p.Mem[opn]
+		switch op % 100 {
+		case 99:
+			// Return.
+			p.pc = opn
+			p.tracef("% 4d: ret(99)\n", opn)
+			return nil
+		case 1:
+			// Add.
+			a, b, ans, err := readParamParamAddr(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("add(1): %s", err)
+			}
+			vc := a.v + b.v
+			if err := p.set(ans, vc); err != nil {
+				return fmt.Errorf("add(1): %s", err)
+			}
+			p.tracef("% 4d: add(1): %s + %s = %d -> *%d\n", opn, a, b, vc, ans)
+			opn += 4
+		case 2:
+			// Multiply.
+			a, b, ans, err := readParamParamAddr(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("mul(2): %s", err)
+			}
+			vc := a.v * b.v
+			if err := p.set(ans, vc); err != nil {
+				return fmt.Errorf("mul(2): %s", err)
+			}
+			p.tracef("% 4d: mul(2): %s + %s = %d -> *%d\n", opn, a, b, vc, ans)
+			opn += 4
+		case 3:
+			// Input.
+			dst, err := readAddr(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("inp(3): %s", err)
+			}
+			p.pc = opn
+			v, err := p.IO.Input()
+			if err != nil {
+				return fmt.Errorf("inp(3): reading input: %w", err)
+			}
+			if err := p.set(dst, v); err != nil {
+				return fmt.Errorf("inp(3): %s", err)
+			}
+			p.tracef("% 4d: inp(3): %d -> *%d\n", opn, v, dst)
+			opn += 2
+		case 4:
+			// Output.
+			src, err := readParam(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("out(4): %s", err)
+			}
+			p.tracef("% 4d: out(4): %s\n", opn, src)
+			err = p.IO.Output(src.v)
+			if err != nil {
+				return fmt.Errorf("out(4): writing output: %w", err)
+			}
+			opn += 2
+		case 5:
+			// Jump-if-True.
+			cond, jump, err := readParamParam(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("jtr(5): %s", err)
+			}
+			if cond.v != 0 {
+				// True.
+				p.tracef("% 4d: jtr(5): %s != 0 => %s\n", opn, cond, jump)
+				opn = int(jump.v)
+			} else {
+				p.tracef("% 4d: jtr(5): %s == 0\n", opn, cond)
+				opn += 3
+			}
+		case 6:
+			// Jump-if-False.
+			cond, jump, err := readParamParam(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("jfa(6): %s", err)
+			}
+			if cond.v == 0 {
+				// False.
+				p.tracef("% 4d: jfa(6): %s == 0 => %s\n", opn, cond, jump)
+				opn = int(jump.v)
+			} else {
+				p.tracef("% 4d: jfa(6): %s != 0\n", opn, cond)
+				opn += 3
+			}
+		case 7:
+			// Less than.
+			a, b, ans, err := readParamParamAddr(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("les(7): %s", err)
+			}
+			var v int64
+			if a.v < b.v {
+				v = 1
+			}
+			if err := p.set(ans, v); err != nil {
+				return fmt.Errorf("les(7): %s", err)
+			}
+			p.tracef("% 4d: les(7): %s < %s = %d -> *%d\n", opn, a, b, v, ans)
+			opn += 4
+		case 8:
+			// Equals.
+			a, b, ans, err := readParamParamAddr(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("equ(8): %s", err)
+			}
+			var v int64
+			if a.v == b.v {
+				v = 1
+			}
+			if err := p.set(ans, v); err != nil {
+				return fmt.Errorf("equ(8): %s", err)
+			}
+			p.tracef("% 4d: equ(8): %s == %s = %d -> *%d\n", opn, a, b, v, ans)
+			opn += 4
+		case 9:
+			// Base.
+			base, err := readParam(p, opn, 1)
+			if err != nil {
+				return fmt.Errorf("bas(9): %s", err)
+			}
+			b := p.base
+			p.base += int(base.v)
+			p.tracef("% 4d: bas(9): %d + %s ~> %d\n", opn, b, base, p.base)
+			opn += 2
+		default:
+			return fmt.Errorf("intcode: unrecognised op %d at position %d", op%100, opn)
+		}
+	}
+	return errors.New("intcode: no operation")
+}
+
+type param struct {
+	f int
+	p int
+	r int
+	v int64
+}
+
+func (p param) String() string {
+	switch p.f {
+	case flagLit:
+		return strconv.FormatInt(p.v, 10)
+	case flagPos:
+		return "(*" + strconv.Itoa(p.p) + " -> " + strconv.FormatInt(p.v, 10) + ")"
+	case flagRel:
+		return fmt.Sprintf("(*%d%+d -> %d)", p.p-p.r, p.r, p.v)
+	}
+	return fmt.Sprintf("%#v", p)
+}
+
+func readParamParamAddr(pr *Prog, opn int, n int) (a param, b param, addr int, err error) {
+	a, b, err = readParamParam(pr, opn, n)
+	if err != nil {
+		return
+	}
+	addr, err = readAddr(pr, opn, n+2)
+	return
+}
+
+func readParamParam(pr *Prog, opn int, n int) (a param, b param, err error) {
+	a, err = readParam(pr, opn, n)
+	if err != nil {
+		return
+	}
+	b, err = readParam(pr, opn, n+1)
+	return
+}
+
+func readParam(pr *Prog, opn int, n int) (param, error) {
+	f := readFlag(pr, opn, n)
+	arg, err := pr.get(opn + n)
+	if err != nil {
+		return param{}, err
+	}
+	var p, r int
+	switch f {
+	case flagLit:
+		return param{f: f, p: -1, v: arg}, nil
+	case flagPos:
+		p = int(arg)
+	case flagRel:
+		r = int(arg)
+		p = pr.base + r
+	}
+	v, err := pr.get(p)
+	if err != nil {
+		return param{}, err
+	}
+	return param{f: f, p: p, r: r, v: v}, nil
+}
+
+func readAddr(pr *Prog, opn int, n int) (int, error) {
+	f := readFlag(pr, opn, n)
+	arg, err := pr.get(opn + n)
+	if err != nil {
+		return -1, err
+	}
+	var addr int
+	switch f {
+	case flagLit:
+		return -1, fmt.Errorf("wanted pointer but literal at position %d", opn+n)
+	case flagPos:
+		addr = int(arg)
+	case flagRel:
+		addr = pr.base + int(arg)
+	default:
+		return -1, fmt.Errorf("unrecognised flag %d", f)
+	}
+	if addr < 0 {
+		return -1, fmt.Errorf("negative address %d at position %d", addr, opn+n)
+	}
+	return addr, nil
+}
+
+// readFlag returns the mode of the nth parameter of the operation at opn, which
+// must be within the program.
+func readFlag(pr *Prog, opn int, n int) int {
+	return int((pr.Mem[opn] / exp10(n+1)) % 10)
+}
+
+const (
+	flagPos = 0 // Positional mode: the value is at the address
+	flagLit = 1 // Immediate mode: the value is literal
+	flagRel = 2 // Relative mode: the value is at the address relative to the root
+)
+
+func exp10(n int) int64 {
+	switch n {
+	case 0:
+		return 1e0
+	case 1:
+		return 1e1
+	case 2:
+		return 1e2
+	case 3:
+		return 1e3
+	case 4:
+		return 1e4
+	}
+	v := int64(1)
+	for ; n > 0; n-- {
+		v *= 10
+	}
+	return v
+}
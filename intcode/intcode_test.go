@@ -0,0 +1,57 @@
+package intcode
+
+import (
+	"fmt"
+	"testing"
+)
+
+// nopIO is the IO of programs which neither read nor write.
+type nopIO struct{}
+
+func (nopIO) Input() (int64, error) { return 0, fmt.Errorf("no input") }
+func (nopIO) Output(int64) error    { return nil }
+
+func TestExec(t *testing.T) {
+	for _, test := range []struct {
+		code string
+		err  string
+		addr int
+		exp  int64
+	}{
+		{"1,0,0,0,99", "", 0, 2},
+		{"1101,1,1,5,99", "", 5, 2},        // Just beyond the program.
+		{"1101,1,1,12,99", "", 12, 2},      // Beyond twice the program.
+		{"109,3,21101,1,1,2,99", "", 5, 2}, // Relative to the base.
+		{"1101,1,1,-1,99", "add(1): negative address -1 at position 3", 0, 0},
+		{"1,-1,0,0,99", "add(1): negative address -1", 0, 0},
+		{"109,-5,22201,0,0,0,99", "add(1): negative address -5", 0, 0},
+		{"1105,1,-3", "intcode: jump to negative position -3", 0, 0},
+	} {
+		code, err := Parse(test.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := &Prog{IO: nopIO{}, Mem: code}
+		err = Exec(p)
+		if got := fmt.Sprint(err); (err != nil || test.err != "") && got != test.err {
+			t.Errorf("%s: error %q, expected %q", test.code, got, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if v, _ := p.get(test.addr); v != test.exp {
+			t.Errorf("%s: [%d] = %d, expected %d", test.code, test.addr, v, test.exp)
+		}
+	}
+}
+
+func TestSetEmpty(t *testing.T) {
+	var p Prog
+	if err := p.set(3, 7); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := p.get(3); v != 7 {
+		t.Errorf("[3] = %d, expected 7", v)
+	}
+}
@@ -0,0 +1,138 @@
+package intcode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// event is a single recorded input or output value and the step of the program
+// at which it occurred.
+type event struct {
+	step int64
+	dir  string // "in" or "out"
+	v    int64
+}
+
+func (e event) String() string {
+	return fmt.Sprintf("%d %s %d", e.step, e.dir, e.v)
+}
+
+// Recorder passes input and output through to IO, writing each value to W as
+// a line of the form "<step> in|out <value>", where step is that of Prog.
+type Recorder struct {
+	IO   IO
+	W    io.Writer
+	Prog *Prog
+}
+
+func (r *Recorder) Input() (int64, error) {
+	v, err := r.IO.Input()
+	if err != nil {
+		return v, err
+	}
+	return v, r.write(event{r.Prog.steps, "in", v})
+}
+
+func (r *Recorder) Output(n int64) error {
+	if err := r.write(event{r.Prog.steps, "out", n}); err != nil {
+		return err
+	}
+	return r.IO.Output(n)
+}
+
+func (r *Recorder) write(e event) error {
+	_, err := fmt.Fprintln(r.W, e)
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	return nil
+}
+
+// Replayer feeds the inputs of a recording back into a program, failing if the
+// program's outputs (or the steps at which they occur) differ from those
+// recorded. Each output is also passed on to Out, if set.
+type Replayer struct {
+	Out IO
+
+	events []event
+	next   int
+	p      *Prog
+}
+
+// NewReplayer reads a recording made by a Recorder from r, for replay into p.
+func NewReplayer(r io.Reader, p *Prog) (*Replayer, error) {
+	var events []event
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		f := strings.Fields(s.Text())
+		if len(f) == 0 {
+			continue
+		}
+		if len(f) != 3 || (f[1] != "in" && f[1] != "out") {
+			return nil, fmt.Errorf("replay: line %d: expected \"<step> in|out <value>\" but got %q", line, s.Text())
+		}
+		step, err := strconv.ParseInt(f[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("replay: line %d: bad step: %w", line, err)
+		}
+		v, err := strconv.ParseInt(f[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("replay: line %d: bad value: %w", line, err)
+		}
+		events = append(events, event{step, f[1], v})
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	return &Replayer{events: events, p: p}, nil
+}
+
+// expect returns the next recorded event, checking that it has the direction
+// dir and that it occurs at the program's current step.
+func (r *Replayer) expect(dir string) (event, error) {
+	if r.next >= len(r.events) {
+		return event{}, fmt.Errorf("replay: unexpected %s at step %d after end of recording", dir, r.p.steps)
+	}
+	e := r.events[r.next]
+	r.next++
+	if e.dir != dir {
+		return e, fmt.Errorf("replay: %s at step %d but recorded %q", dir, r.p.steps, e)
+	}
+	if e.step != r.p.steps {
+		return e, fmt.Errorf("replay: %s at step %d but recorded %q", dir, r.p.steps, e)
+	}
+	return e, nil
+}
+
+func (r *Replayer) Input() (int64, error) {
+	e, err := r.expect("in")
+	if err != nil {
+		return 0, err
+	}
+	return e.v, nil
+}
+
+func (r *Replayer) Output(n int64) error {
+	e, err := r.expect("out")
+	if err != nil {
+		return err
+	}
+	if e.v != n {
+		return fmt.Errorf("replay: output %d at step %d but recorded %q", n, r.p.steps, e)
+	}
+	if r.Out != nil {
+		return r.Out.Output(n)
+	}
+	return nil
+}
+
+// Done returns an error if any recorded events were not replayed.
+func (r *Replayer) Done() error {
+	if r.next < len(r.events) {
+		return fmt.Errorf("replay: program finished with %d recorded events remaining, next %q", len(r.events)-r.next, r.events[r.next])
+	}
+	return nil
+}
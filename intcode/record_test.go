@@ -0,0 +1,104 @@
+package intcode
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sliceIO feeds a program each of its inputs in turn, and collects its outputs.
+type sliceIO struct {
+	in, out []int64
+}
+
+func (s *sliceIO) Input() (int64, error) {
+	if len(s.in) == 0 {
+		return 0, io.EOF
+	}
+	v := s.in[0]
+	s.in = s.in[1:]
+	return v, nil
+}
+
+func (s *sliceIO) Output(n int64) error {
+	s.out = append(s.out, n)
+	return nil
+}
+
+// doubleThenEcho outputs twice its first input, and then its second input.
+const doubleThenEcho = "3,13,1002,13,2,13,4,13,3,13,4,13,99,0"
+
+func mustParse(t *testing.T, code string) []int64 {
+	t.Helper()
+	m, err := Parse(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestRecordReplay(t *testing.T) {
+	var rec strings.Builder
+	p := &Prog{Mem: mustParse(t, doubleThenEcho)}
+	p.IO = &Recorder{IO: &sliceIO{in: []int64{5, 7}}, W: &rec, Prog: p}
+	if err := Exec(p); err != nil {
+		t.Fatal(err)
+	}
+	if exp := "1 in 5\n3 out 10\n4 in 7\n5 out 7\n"; rec.String() != exp {
+		t.Fatalf("recorded %q, expected %q", rec.String(), exp)
+	}
+
+	for _, test := range []struct {
+		name, rec string
+		err, done string
+	}{
+		{"same", rec.String(), "", ""},
+		{"output", "1 in 5\n3 out 11\n", `out(4): writing output: replay: output 10 at step 3 but recorded "3 out 11"`, ""},
+		{"step", "1 in 5\n4 out 10\n", `out(4): writing output: replay: out at step 3 but recorded "4 out 10"`, ""},
+		{"direction", "1 in 5\n3 in 10\n", `out(4): writing output: replay: out at step 3 but recorded "3 in 10"`, ""},
+		{"short", "1 in 5\n3 out 10\n", `inp(3): reading input: replay: unexpected in at step 4 after end of recording`, ""},
+		{"leftover", rec.String() + "6 out 1\n", "", `replay: program finished with 1 recorded events remaining, next "6 out 1"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p := &Prog{Mem: mustParse(t, doubleThenEcho)}
+			rp, err := NewReplayer(strings.NewReader(test.rec), p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := &sliceIO{}
+			rp.Out = out
+			p.IO = rp
+
+			err = Exec(p)
+			if got := fmt.Sprint(err); (err != nil || test.err != "") && got != test.err {
+				t.Fatalf("Exec error %q, expected %q", got, test.err)
+			}
+			if err != nil {
+				return
+			}
+			if exp := []int64{10, 7}; !reflect.DeepEqual(out.out, exp) {
+				t.Errorf("replayed outputs %v, expected %v", out.out, exp)
+			}
+			err = rp.Done()
+			if got := fmt.Sprint(err); (err != nil || test.done != "") && got != test.done {
+				t.Errorf("Done error %q, expected %q", got, test.done)
+			}
+		})
+	}
+}
+
+func TestNewReplayerError(t *testing.T) {
+	for _, test := range []struct{ rec, err string }{
+		{"1 in", `replay: line 1: expected "<step> in|out <value>" but got "1 in"`},
+		{"\n1 sideways 2", `replay: line 2: expected "<step> in|out <value>" but got "1 sideways 2"`},
+		{"x in 2", `replay: line 1: bad step: strconv.ParseInt: parsing "x": invalid syntax`},
+		{"1 in y", `replay: line 1: bad value: strconv.ParseInt: parsing "y": invalid syntax`},
+	} {
+		_, err := NewReplayer(strings.NewReader(test.rec), &Prog{})
+		if got := fmt.Sprint(err); got != test.err {
+			t.Errorf("NewReplayer(%q) error %q, expected %q", test.rec, got, test.err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package wires
+
+import "sort"
+
+// ClosestTaxi returns the Taxi/Manhattan distance from (x, y) to the closest
+// point at which wires a and b cross, other than (x, y) itself, or -1 if they
+// don't cross.
+func ClosestTaxi(x, y int64, a, b []Rect) int64 {
+	dist := int64(-1)
+	eachCrossing(Point(x, y), a, b, func(c Crossing) {
+		if dist < 0 || c.Taxi < dist {
+			dist = c.Taxi
+		}
+	})
+	return dist
+}
+
+// ClosestSteps identifies the minimum number of steps taken along a and b from
+// orig to find an intersection point, or -1 if they don't cross.
+func ClosestSteps(orig Rect, a, b []Rect) int64 {
+	dist := int64(-1)
+	eachCrossing(orig, a, b, func(c Crossing) {
+		if dist < 0 || c.Steps < dist {
+			dist = c.Steps
+		}
+	})
+	return dist
+}
+
+// eachCrossing calls f with each crossing of a and b, in no particular order.
+func eachCrossing(orig Rect, a, b []Rect, f func(Crossing)) {
+	aw, bw := walk(orig, a), walk(orig, b)
+	Sweep(a, b, func(i, j int) {
+		if c, ok := crossing(orig, a[i], b[j], aw[i], bw[j]); ok {
+			f(c)
+		}
+	})
+}
+
+// walked is the position of a wire at the start of each of its moves.
+type walked struct {
+	start Rect  // The point at which the move starts.
+	steps int64 // Steps taken to reach start.
+}
+
+func walk(orig Rect, w []Rect) []walked {
+	ws := make([]walked, len(w))
+	pos, steps := orig, int64(0)
+	for i, r := range w {
+		ws[i] = walked{pos, steps}
+		pos = end(pos, r)
+		steps += r.Steps()
+	}
+	return ws
+}
+
+// Ends returns the point at which each move of w ends, having started from
+// orig.
+func Ends(orig Rect, w []Rect) []Rect {
+	ends := make([]Rect, len(w))
+	pos := orig
+	for i, r := range w {
+		pos = end(pos, r)
+		ends[i] = pos
+	}
+	return ends
+}
+
+// end returns the point at which the move r, started from pos, ends.
+func end(pos, r Rect) Rect {
+	a, b := r.Corners()
+	if b != pos {
+		return b
+	}
+	return a
+}
+
+// crossing returns the crossing of the intersecting moves ar and br, started
+// from aw and bw, or false if they only meet at orig.
+//
+// Where the moves run over each other, the closest point and the point taking
+// the fewest steps can each be anywhere along the run, and needn't be the same
+// point.
+func crossing(orig, ar, br Rect, aw, bw walked) (Crossing, bool) {
+	at := ar.Intersection(br)
+	taxiAt, taxi, ok := least(at, orig, func(p Rect) int64 {
+		return p.Dist(orig)
+	})
+	if !ok {
+		return Crossing{}, false
+	}
+	stepsAt, steps, _ := least(at, orig, func(p Rect) int64 {
+		return aw.steps + p.ChebyshevDist(aw.start) + bw.steps + p.ChebyshevDist(bw.start)
+	})
+	return Crossing{
+		At:      at,
+		Taxi:    taxi,
+		TaxiAt:  taxiAt,
+		Steps:   steps,
+		StepsAt: stepsAt,
+	}, true
+}
+
+// number is a measure of a point, for least to minimise.
+type number interface{ ~int64 | ~float64 }
+
+// least returns the point of the line r, other than skip, at which cost is
+// least, or false if r covers nothing but skip.
+//
+// The cost must be either convex or concave along r, as distances and sums or
+// maxima of distances are, and as minima of steps along a run are. Then the
+// least cost either side of skip is at one of its ends, or found by a binary
+// search for where the cost stops falling.
+func least[N number](r, skip Rect, cost func(Rect) N) (p Rect, c N, ok bool) {
+	// Points along r are a+t*(dx, dy) for t from 0 to n.
+	a, b := r.Corners()
+	n := max(b.X1-a.X1, b.Y1-a.Y1)
+	dx, dy := sign(b.X1-a.X1), sign(b.Y1-a.Y1)
+	at := func(t int64) Rect { return Point(a.X1+t*dx, a.Y1+t*dy) }
+	try := func(t int64) {
+		if t < 0 || t > n {
+			return
+		}
+		q := at(t)
+		if q == skip {
+			return
+		}
+		if qc := cost(q); !ok || qc < c {
+			p, c, ok = q, qc, true
+		}
+	}
+	search := func(lo, hi int64) {
+		try(lo)
+		try(hi)
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			if cost(at(mid+1)) < cost(at(mid)) {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		try(lo)
+	}
+
+	// Search either side of skip, if it's on r.
+	ts := int64(-1)
+	if skip.X1 == skip.X2 && skip.Y1 == skip.Y2 && r.Intersects(skip) {
+		ts = (skip.X1-a.X1)*dx + (skip.Y1-a.Y1)*dy
+		if dx != 0 && dy != 0 {
+			ts = (skip.X1 - a.X1) * dx
+		}
+	}
+	if ts < 0 {
+		search(0, n)
+	} else {
+		if ts > 0 {
+			search(0, ts-1)
+		}
+		if ts < n {
+			search(ts+1, n)
+		}
+	}
+	return p, c, ok
+}
+
+func sign(v int64) int64 {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+// nowhere is a rectangle containing no points, for least to skip.
+var nowhere = Rect{X1: 1, X2: 0}
+
+// crosses returns the indexes of the intersecting segments of a and b, ordered
+// along a and then b.
+func crosses(a, b []Rect) [][2]int {
+	var ijs [][2]int
+	Sweep(a, b, func(i, j int) { ijs = append(ijs, [2]int{i, j}) })
+	sort.Slice(ijs, func(m, n int) bool {
+		if ijs[m][0] != ijs[n][0] {
+			return ijs[m][0] < ijs[n][0]
+		}
+		return ijs[m][1] < ijs[n][1]
+	})
+	return ijs
+}
+
+// closest returns the least of f over every pair of wires, ignoring the pairs
+// for which f returns -1.
+func closest(wires [][]Rect, f func(a, b []Rect) int64) int64 {
+	dist := int64(-1)
+	for i := range wires {
+		for j := i + 1; j < len(wires); j++ {
+			d := f(wires[i], wires[j])
+			if d >= 0 && (dist < 0 || d < dist) {
+				dist = d
+			}
+		}
+	}
+	return dist
+}
+
+// ClosestTaxiAll is ClosestTaxi over every pair of wires.
+func ClosestTaxiAll(x, y int64, wires [][]Rect) int64 {
+	return closest(wires, func(a, b []Rect) int64 { return ClosestTaxi(x, y, a, b) })
+}
+
+// ClosestStepsAll is ClosestSteps over every pair of wires.
+func ClosestStepsAll(orig Rect, wires [][]Rect) int64 {
+	return closest(wires, func(a, b []Rect) int64 { return ClosestSteps(orig, a, b) })
+}
@@ -0,0 +1,52 @@
+package wires
+
+import "testing"
+
+func TestClosest(t *testing.T) {
+	for _, test := range []struct {
+		a, b        string
+		taxi, steps int64
+	}{
+		// The examples of the puzzle.
+		{"R8,U5,L5,D3", "U7,R6,D4,L4", 6, 30},
+		{"R75,D30,R83,U83,L12,D49,R71,U7,L72", "U62,R66,U55,R34,D71,R55,D58,R83", 159, 610},
+		{"R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51", "U98,R91,D20,R16,D67,R40,U7,R15,U6,R7", 135, 410},
+
+		{"R10", "U1", -1, -1},                       // Only meeting at the origin
+		{"R5", "R3,U1", 1, 2},                       // Running together from the origin
+		{"R10", "U1,R8,D1,L6", 2, 18},               // Running over each other in opposite directions
+		{"R10", "U1,R2,D1,R5", 2, 6},                // Running together in the same direction
+		{"R5,L3,U2", "U1,R4,D1", 3, 10},             // Doubling back over itself
+		{"U3,R6,D3", "D1,R3,U4,R5,U2,L2,D4", 6, 14}, // Crossing repeatedly
+		{"L4,U2,R8", "U2", 2, 12},                   // Meeting at a corner
+		{"D2,R2,U2", "R1,D4,R2,U4,L1,D2,L2,U2", 1, 6},
+		{"U2,R2,D1,L4", "R1,U1,L1", 1, 4}, // Crossing where the first crosses itself
+		{"NE3,SE3", "N2,E6", 4, 6},        // Crossing diagonals
+		{"NE2", "E1,NW2", -1, -1},         // Crossing between points
+		{"NE3", "N1,E1,NE3", 2, 3},        // Running along the same diagonal
+	} {
+		a, b := mustParse(t, test.a), mustParse(t, test.b)
+		taxi := ClosestTaxi(0, 0, a, b)
+		steps := ClosestSteps(Point(0, 0), a, b)
+		if taxi != test.taxi || steps != test.steps {
+			t.Errorf("%s / %s: taxi %d, steps %d, expected taxi %d, steps %d", test.a, test.b, taxi, steps, test.taxi, test.steps)
+		}
+		if ClosestTaxi(0, 0, b, a) != taxi || ClosestSteps(Point(0, 0), b, a) != steps {
+			t.Errorf("%s / %s: differs when swapped", test.b, test.a)
+		}
+	}
+}
+
+// The closest crossings must be those found by tracing the wires a step at a
+// time.
+func TestClosestMatchesTrace(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		a, b := generate(seed, 200, 1+seed%20)
+		_, tracedTaxi, tracedSteps := trace(a, b)
+		taxi := ClosestTaxi(0, 0, a, b)
+		steps := ClosestSteps(Point(0, 0), a, b)
+		if taxi != tracedTaxi || steps != tracedSteps {
+			t.Errorf("seed %d: taxi %d, steps %d, expected taxi %d, steps %d", seed, taxi, steps, tracedTaxi, tracedSteps)
+		}
+	}
+}
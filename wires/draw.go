@@ -0,0 +1,74 @@
+package wires
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/icio/adventofcode2019/grid"
+)
+
+// drawn is a cell of a drawing of wires.
+type drawn struct {
+	c    byte
+	wire int
+}
+
+// Draw draws the wires, started from orig, as in the puzzle: each move as -, |,
+// / or \, with + at each corner, X where different wires cross, and o at the
+// origin. The drawing is bordered by a row or column of empty cells, drawn as
+// dots, on each side.
+func Draw(w io.Writer, orig Rect, wires [][]Rect) error {
+	g := grid.New[drawn]()
+	set := func(x, y int64, d drawn) {
+		p := grid.Point{X: x, Y: y}
+		if was, ok := g.Lookup(p); ok {
+			switch {
+			case was.wire != d.wire:
+				d.c = 'X'
+			case was.c == 'X':
+				return
+			case was.c != d.c:
+				d.c = '+'
+			}
+		}
+		g.Set(p, d)
+	}
+
+	line := func(r Rect) byte {
+		switch {
+		case r.Slope > 0:
+			return '\\'
+		case r.Slope < 0:
+			return '/'
+		case r.Y1 == r.Y2:
+			return '-'
+		}
+		return '|'
+	}
+	for n, w := range wires {
+		ends := Ends(orig, w)
+		for i, r := range w {
+			c := line(r)
+			r.Each(func(p Rect) { set(p.X1, p.Y1, drawn{c, n}) })
+			// Turning onto the next move makes a corner.
+			if i+1 < len(w) && c != line(w[i+1]) {
+				set(ends[i].X1, ends[i].Y1, drawn{'+', n})
+			}
+		}
+	}
+	g.Set(grid.Point{X: orig.X1, Y: orig.Y1}, drawn{c: 'o'})
+
+	min, max := g.Bounds()
+	bw := bufio.NewWriter(w)
+	for y := min.Y - 1; y <= max.Y+1; y++ {
+		for x := min.X - 1; x <= max.X+1; x++ {
+			d, ok := g.Lookup(grid.Point{X: x, Y: y})
+			if !ok {
+				d.c = '.'
+			}
+			bw.WriteByte(d.c)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
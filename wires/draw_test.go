@@ -0,0 +1,65 @@
+package wires
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDraw(t *testing.T) {
+	for _, test := range []struct {
+		wires []string
+		exp   string
+	}{
+		// The example of the puzzle.
+		{[]string{"R8,U5,L5,D3", "U7,R6,D4,L4"}, `
+...........
+.+-----+...
+.|.....|...
+.|..+--X-+.
+.|..|..|.|.
+.|.-X--+.|.
+.|..|....|.
+.|.......|.
+.o-------+.
+...........
+`},
+		// Doubling back along itself, and running along another.
+		{[]string{"R5,L3,U2,R4,D4", "R10"}, `
+.............
+...+---+.....
+...|...|.....
+.oXXXXXX----.
+.......|.....
+.......|.....
+.............
+`},
+		// Moving diagonally.
+		{[]string{"NE2,SE2", "E4"}, `
+.......
+...+...
+../.\..
+.o---X.
+.......
+`},
+		// Crossing itself.
+		{[]string{"U2,R2,D1,L4"}, `
+.......
+...+-+.
+.--+-+.
+...o...
+.......
+`},
+	} {
+		var ws [][]Rect
+		for _, w := range test.wires {
+			ws = append(ws, mustParse(t, w))
+		}
+		var b strings.Builder
+		if err := Draw(&b, Point(0, 0), ws); err != nil {
+			t.Fatal(err)
+		}
+		if exp := strings.TrimPrefix(test.exp, "\n"); b.String() != exp {
+			t.Errorf("Draw(%q) =\n%s\nexpected\n%s", test.wires, b.String(), exp)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package wires
+
+import "math/rand"
+
+// Generate returns a wire of n random moves starting from (x, y), each up to
+// maxDist long in one of dirs (such as Axes or Compass), for stress testing.
+func Generate(r *rand.Rand, x, y int64, n int, maxDist int64, dirs []string) []Rect {
+	w := make([]Rect, n)
+	for i := range w {
+		v, _ := vectorBounds(dirs[r.Intn(len(dirs))], 1+r.Int63n(maxDist))
+		w[i] = v.add(x, y)
+		x += v.X1 + v.X2
+		y += v.Y1 + v.Y2
+	}
+	return w
+}
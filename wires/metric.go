@@ -0,0 +1,124 @@
+package wires
+
+import (
+	"math"
+	"sort"
+)
+
+// Metric measures how close a crossing is, by its point p, and the steps a and
+// b taken along each wire to reach it from orig.
+type Metric struct {
+	Name, Desc string
+	Measure    func(orig, p Rect, a, b int64) float64
+}
+
+// Metrics are the ways crossings can be ranked.
+var Metrics = []Metric{
+	{"taxi", "Taxi/Manhattan distance from the origin", func(orig, p Rect, a, b int64) float64 {
+		return float64(p.Dist(orig))
+	}},
+	{"chebyshev", "Chebyshev distance from the origin: the most steps in either direction", func(orig, p Rect, a, b int64) float64 {
+		return float64(p.ChebyshevDist(orig))
+	}},
+	{"euclidean", "straight-line distance from the origin", func(orig, p Rect, a, b int64) float64 {
+		return math.Hypot(float64(p.X1-orig.X1), float64(p.Y1-orig.Y1))
+	}},
+	{"steps", "steps along both wires", func(orig, p Rect, a, b int64) float64 {
+		return float64(a + b)
+	}},
+	{"steps-max", "steps along the wire taking longer", func(orig, p Rect, a, b int64) float64 {
+		return float64(max(a, b))
+	}},
+	{"steps-min", "steps along the wire taking fewer", func(orig, p Rect, a, b int64) float64 {
+		return float64(min(a, b))
+	}},
+	{"steps-a", "steps along the first wire of each pair", func(orig, p Rect, a, b int64) float64 {
+		return float64(a)
+	}},
+	{"steps-b", "steps along the second wire of each pair", func(orig, p Rect, a, b int64) float64 {
+		return float64(b)
+	}},
+}
+
+// MetricNamed returns the metric called name, or false if there isn't one.
+func MetricNamed(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Ranked is a crossing of wires A and B, at its closest point At.
+type Ranked struct {
+	A        int     `json:"a"`
+	B        int     `json:"b"`
+	At       Rect    `json:"at"`
+	Distance float64 `json:"distance"`
+}
+
+// Rank returns the k crossings of any pair of wires, all started from orig,
+// which are closest by m, or all of them if k isn't positive. Where wires run
+// over each other, the run is ranked once, by its closest point.
+func Rank(orig Rect, wires [][]Rect, m Metric, k int) []Ranked {
+	var rs []Ranked
+	for i := range wires {
+		for j := i + 1; j < len(wires); j++ {
+			rs = append(rs, rank(orig, wires[i], wires[j], i, j, m)...)
+		}
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		return rs[i].Distance < rs[j].Distance
+	})
+	if k > 0 && len(rs) > k {
+		rs = rs[:k]
+	}
+	return rs
+}
+
+// rank measures each crossing of wires a and b, indexed ai and bi.
+func rank(orig Rect, a, b []Rect, ai, bi int, m Metric) []Ranked {
+	var rs []Ranked
+	var runs []Rect
+	seen := make(map[Rect]int)
+	aw, bw := walk(orig, a), walk(orig, b)
+	for _, ij := range crosses(a, b) {
+		i, j := ij[0], ij[1]
+		run := a[i].Intersection(b[j])
+		at, d, ok := least(run, orig, func(p Rect) float64 {
+			return m.Measure(orig, p,
+				aw[i].steps+p.ChebyshevDist(aw[i].start),
+				bw[j].steps+p.ChebyshevDist(bw[j].start))
+		})
+		if !ok {
+			continue
+		}
+		// Where a wire visits the same run more than once, several pairs of
+		// moves cross there: keep the lowest measure of them.
+		if n, ok := seen[run]; ok {
+			if d < rs[n].Distance {
+				rs[n].At, rs[n].Distance = at, d
+			}
+			continue
+		}
+		seen[run] = len(rs)
+		rs = append(rs, Ranked{A: ai, B: bi, At: at, Distance: d})
+		runs = append(runs, run)
+	}
+
+	// A point or run within a longer run is ranked as part of it.
+	into := within(runs)
+	for n, r := range rs {
+		if m := into[n]; m != n && r.Distance < rs[m].Distance {
+			rs[m].At, rs[m].Distance = r.At, r.Distance
+		}
+	}
+	merged := rs[:0]
+	for n, r := range rs {
+		if into[n] == n {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,56 @@
+package wires
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Ranking by each metric must find the same closest crossing as measuring
+// every point which the wires visit.
+func TestRankMatchesVisits(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		a, b := generate(seed, 100, 1+seed%20)
+		_, aSteps := visit(a)
+		_, bSteps := visit(b)
+		for _, m := range Metrics {
+			exp := math.Inf(1)
+			for p, as := range aSteps {
+				if bs, ok := bSteps[p]; ok && p != Point(0, 0) {
+					exp = math.Min(exp, m.Measure(Point(0, 0), p, as, bs))
+				}
+			}
+			got := math.Inf(1)
+			if rs := Rank(Point(0, 0), [][]Rect{a, b}, m, 1); len(rs) > 0 {
+				got = rs[0].Distance
+			}
+			if got != exp {
+				t.Errorf("seed %d: closest by %s is %g, expected %g", seed, m.Name, got, exp)
+			}
+		}
+	}
+}
+
+// A crossing within a run of the same pair of wires is ranked as part of it.
+func TestRankWithinRun(t *testing.T) {
+	taxi, _ := MetricNamed("taxi")
+	rs := Rank(Point(0, 0), [][]Rect{mustParse(t, "R8,U5,L5,D3"), mustParse(t, "R3,U9")}, taxi, 0)
+	exp := []Ranked{
+		{A: 0, B: 1, At: Point(1, 0), Distance: 1},
+		{A: 0, B: 1, At: Point(3, -2), Distance: 5},
+	}
+	if !reflect.DeepEqual(rs, exp) {
+		t.Errorf("Rank = %+v, expected %+v", rs, exp)
+	}
+}
+
+func TestMetricNamed(t *testing.T) {
+	for _, m := range Metrics {
+		if got, ok := MetricNamed(m.Name); !ok || got.Name != m.Name {
+			t.Errorf("MetricNamed(%q) = %q, %t", m.Name, got.Name, ok)
+		}
+	}
+	if _, ok := MetricNamed("nope"); ok {
+		t.Error(`MetricNamed("nope") found a metric`)
+	}
+}
@@ -0,0 +1,201 @@
+package wires
+
+import (
+	"sort"
+
+	"github.com/icio/adventofcode2019/grid"
+)
+
+// Crossing is where a pair of wires cross.
+type Crossing struct {
+	At Rect `json:"at"` // The point or run common to both wires.
+
+	// Taxi is the Taxi/Manhattan distance from the origin of TaxiAt, the
+	// closest point of At other than the origin itself.
+	Taxi   int64 `json:"taxi"`
+	TaxiAt Rect  `json:"taxi_at"`
+
+	// Steps is the fewest steps taken along both wires to reach StepsAt, a
+	// point of At.
+	Steps   int64 `json:"steps"`
+	StepsAt Rect  `json:"steps_at"`
+}
+
+// Pair reports the crossings of wires A and B, indexed from 0.
+type Pair struct {
+	A         int        `json:"a"`
+	B         int        `json:"b"`
+	Crossings []Crossing `json:"crossings"`
+
+	// ClosestTaxi and ClosestSteps are the least Taxi and Steps of the
+	// Crossings, or -1 if there are none.
+	ClosestTaxi  int64 `json:"closest_taxi"`
+	ClosestSteps int64 `json:"closest_steps"`
+}
+
+// Junction is a point crossed by several wires.
+type Junction struct {
+	X     int64 `json:"x"`
+	Y     int64 `json:"y"`
+	Wires []int `json:"wires"`
+}
+
+// Wire reports on a single wire.
+type Wire struct {
+	Moves         int            `json:"moves"`
+	Steps         int64          `json:"steps"`
+	SelfCrossings []SelfCrossing `json:"self_crossings"`
+}
+
+// Report is the analysis of every pair of a set of wires.
+type Report struct {
+	Wires []Wire `json:"wires"`
+	Pairs []Pair `json:"pairs"`
+
+	// Junctions are the points crossed by at least K of the wires, in row
+	// order.
+	K         int        `json:"k"`
+	Junctions []Junction `json:"junctions"`
+}
+
+// Crossings returns each point or run at which a and b cross, having both
+// started from orig, ordered along a. Crossings only at orig itself are ignored.
+//
+// Where either wire visits a point more than once, its Steps are counted to
+// the first visit.
+func Crossings(orig Rect, a, b []Rect) []Crossing {
+	var cs []Crossing
+	seen := make(map[Rect]int)
+	aw, bw := walk(orig, a), walk(orig, b)
+	for _, ij := range crosses(a, b) {
+		i, j := ij[0], ij[1]
+		c, ok := crossing(orig, a[i], b[j], aw[i], bw[j])
+		if !ok {
+			continue
+		}
+		// Where a wire visits the same point or run more than once, several
+		// pairs of moves cross there: keep the crossing taking the fewest steps.
+		if n, ok := seen[c.At]; ok {
+			if c.Steps < cs[n].Steps {
+				cs[n] = c
+			}
+			continue
+		}
+		seen[c.At] = len(cs)
+		cs = append(cs, c)
+	}
+	return mergeWithin(cs)
+}
+
+// mergeWithin merges each crossing lying within a longer run of another into
+// that run, keeping the fewest steps and least distance of them.
+func mergeWithin(cs []Crossing) []Crossing {
+	ats := make([]Rect, len(cs))
+	for n, c := range cs {
+		ats[n] = c.At
+	}
+	into := within(ats)
+	for n, c := range cs {
+		m := into[n]
+		if m == n {
+			continue
+		}
+		if c.Steps < cs[m].Steps {
+			cs[m].Steps, cs[m].StepsAt = c.Steps, c.StepsAt
+		}
+		if c.Taxi < cs[m].Taxi {
+			cs[m].Taxi, cs[m].TaxiAt = c.Taxi, c.TaxiAt
+		}
+	}
+	merged := cs[:0]
+	for n, c := range cs {
+		if into[n] == n {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
+// within returns the index of the longest of ats containing each of them: its
+// own index unless it lies within a longer run. The runs returned are never
+// within another, as any run containing one containing at would be longer.
+func within(ats []Rect) []int {
+	var runs []int
+	for n, at := range ats {
+		if at.Steps() > 0 {
+			runs = append(runs, n)
+		}
+	}
+	into := make([]int, len(ats))
+	for n, at := range ats {
+		into[n] = n
+		for _, m := range runs {
+			if m != n && ats[m].contains(at) && ats[m].Steps() > ats[into[n]].Steps() {
+				into[n] = m
+			}
+		}
+	}
+	return into
+}
+
+// Analyse reports where each wire crosses itself, the crossings of every pair
+// of wires, all started from orig, and the points at which k or more of them
+// cross.
+func Analyse(orig Rect, wires [][]Rect, k int) *Report {
+	r := &Report{K: k}
+	for _, w := range wires {
+		var steps int64
+		for _, r := range w {
+			steps += r.Steps()
+		}
+		r.Wires = append(r.Wires, Wire{
+			Moves:         len(w),
+			Steps:         steps,
+			SelfCrossings: SelfCrossings(orig, w),
+		})
+	}
+
+	crossed := grid.New[[]int]()
+	for i := range wires {
+		for j := i + 1; j < len(wires); j++ {
+			p := Pair{A: i, B: j, ClosestTaxi: -1, ClosestSteps: -1}
+			p.Crossings = Crossings(orig, wires[i], wires[j])
+			for _, c := range p.Crossings {
+				if p.ClosestTaxi < 0 || c.Taxi < p.ClosestTaxi {
+					p.ClosestTaxi = c.Taxi
+				}
+				if p.ClosestSteps < 0 || c.Steps < p.ClosestSteps {
+					p.ClosestSteps = c.Steps
+				}
+				c.At.Each(func(at Rect) {
+					if at == orig {
+						return
+					}
+					pt := grid.Point{X: at.X1, Y: at.Y1}
+					crossed.Set(pt, addWire(addWire(crossed.Get(pt), i), j))
+				})
+			}
+			r.Pairs = append(r.Pairs, p)
+		}
+	}
+
+	crossed.Each(func(p grid.Point, ws []int) bool {
+		if len(ws) >= k {
+			r.Junctions = append(r.Junctions, Junction{X: p.X, Y: p.Y, Wires: ws})
+		}
+		return true
+	})
+	return r
+}
+
+// addWire adds w to the sorted set ws.
+func addWire(ws []int, w int) []int {
+	i := sort.SearchInts(ws, w)
+	if i < len(ws) && ws[i] == w {
+		return ws
+	}
+	ws = append(ws, 0)
+	copy(ws[i+1:], ws[i:])
+	ws[i] = w
+	return ws
+}
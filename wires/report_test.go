@@ -0,0 +1,62 @@
+package wires
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCrossings(t *testing.T) {
+	got := Crossings(Point(0, 0), mustParse(t, "R8,U5,L5,D3"), mustParse(t, "R3,U9"))
+	exp := []Crossing{
+		{At: rect(0, 3, 0, 0), Taxi: 1, TaxiAt: Point(1, 0), Steps: 2, StepsAt: Point(1, 0)},
+		{At: rect(3, 3, -5, -2), Taxi: 5, TaxiAt: Point(3, -2), Steps: 26, StepsAt: Point(3, -5)},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Crossings = %+v, expected %+v", got, exp)
+	}
+}
+
+// Each place two wires cross is reported once, covering every point at which
+// tracing the wires finds them to cross.
+func TestCrossingsMatchTrace(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		a, b := generate(seed, 200, 1+seed%5)
+		cs := Crossings(Point(0, 0), a, b)
+
+		aMoves, _ := visit(a)
+		bMoves, _ := visit(b)
+		crossed := make(map[Rect]bool)
+		for p := range aMoves {
+			if _, ok := bMoves[p]; ok && p != Point(0, 0) {
+				crossed[p] = true
+			}
+		}
+		covered := make(map[Rect]bool)
+		for m, c := range cs {
+			for n, d := range cs {
+				if m != n && d.At.contains(c.At) {
+					t.Fatalf("seed %d: crossing %+v is within %+v", seed, c.At, d.At)
+				}
+			}
+			c.At.Each(func(p Rect) { covered[p] = true })
+		}
+		delete(covered, Point(0, 0))
+		if !reflect.DeepEqual(covered, crossed) {
+			t.Errorf("seed %d: crossings cover %d points, tracing found %d", seed, len(covered), len(crossed))
+		}
+
+		_, taxi, steps := trace(a, b)
+		closestTaxi, closestSteps := int64(-1), int64(-1)
+		for _, c := range cs {
+			if closestTaxi < 0 || c.Taxi < closestTaxi {
+				closestTaxi = c.Taxi
+			}
+			if closestSteps < 0 || c.Steps < closestSteps {
+				closestSteps = c.Steps
+			}
+		}
+		if closestTaxi != taxi || closestSteps != steps {
+			t.Errorf("seed %d: closest crossing by taxi %d and steps %d, tracing found %d and %d", seed, closestTaxi, closestSteps, taxi, steps)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package wires
+
+import "sort"
+
+// SelfCrossing is a point or run which a wire comes back to.
+type SelfCrossing struct {
+	At Rect `json:"at"`
+
+	// First is the fewest steps taken to reach At, and Again the fewest taken
+	// to come back to it.
+	First int64 `json:"first"`
+	Again int64 `json:"again"`
+}
+
+// SelfCrossings returns each point or run at which w, started from orig,
+// crosses or runs back over itself, ordered by when it comes back. A point
+// within a run that w comes back over is reported as part of the run.
+func SelfCrossings(orig Rect, w []Rect) []SelfCrossing {
+	var cs []SelfCrossing
+	seen := make(map[Rect]int)
+	ws := walk(orig, w)
+
+	// last[j] is the index of the last move before w[j] which goes anywhere,
+	// or -1 if none does.
+	last := make([]int, len(w))
+	for j := range w {
+		last[j] = -1
+		if j > 0 {
+			last[j] = last[j-1]
+			if w[j-1].Steps() > 0 {
+				last[j] = j - 1
+			}
+		}
+	}
+
+	Sweep(w, w, func(i, j int) {
+		if i >= j {
+			return
+		}
+		// Consecutive moves always meet at the corner between them, as do
+		// those separated only by moves going nowhere.
+		skip := nowhere
+		if i >= last[j] {
+			skip = ws[j].start
+		}
+		at := w[i].Intersection(w[j])
+		_, first, ok := least(at, skip, func(p Rect) int64 {
+			return ws[i].steps + p.ChebyshevDist(ws[i].start)
+		})
+		if !ok {
+			return
+		}
+		_, again, _ := least(at, skip, func(p Rect) int64 {
+			return ws[j].steps + p.ChebyshevDist(ws[j].start)
+		})
+		c := SelfCrossing{At: at, First: first, Again: again}
+		if n, ok := seen[at]; ok {
+			cs[n] = fewest(cs[n], c)
+			return
+		}
+		seen[at] = len(cs)
+		cs = append(cs, c)
+	})
+
+	ats := make([]Rect, len(cs))
+	for n, c := range cs {
+		ats[n] = c.At
+	}
+	into := within(ats)
+	for n, c := range cs {
+		if m := into[n]; m != n {
+			cs[m] = fewest(cs[m], c)
+		}
+	}
+	merged := cs[:0]
+	for n, c := range cs {
+		if into[n] == n {
+			merged = append(merged, c)
+		}
+	}
+	cs = merged
+
+	sort.Slice(cs, func(m, n int) bool {
+		if cs[m].Again != cs[n].Again {
+			return cs[m].Again < cs[n].Again
+		}
+		return cs[m].First < cs[n].First
+	})
+	return cs
+}
+
+// fewest returns c with the fewest steps to reach it and come back of c and d.
+func fewest(c, d SelfCrossing) SelfCrossing {
+	c.First = min(c.First, d.First)
+	c.Again = min(c.Again, d.Again)
+	return c
+}
@@ -0,0 +1,27 @@
+package wires
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelfCrossings(t *testing.T) {
+	for _, test := range []struct {
+		wire string
+		exp  []SelfCrossing
+	}{
+		{"R8,U5,L5,D3", nil},
+		{"U2,R2,D1,L4", []SelfCrossing{{At: Point(0, -1), First: 1, Again: 7}}},
+		{"R5,L3,U2", []SelfCrossing{{At: rect(2, 5, 0, 0), First: 2, Again: 6}}}, // Doubling back, and turning within the run
+		{"R2,U0,U2", nil},    // Moving nowhere between moves
+		{"R2,U0,U0,U2", nil}, // Moving nowhere twice
+		{"U0,R2,U0", nil},    // Moving nowhere first and last
+		{"U2,R2,D1,L0,L4", []SelfCrossing{{At: Point(0, -1), First: 1, Again: 7}}},
+		{"R2,U2,L2,D2", []SelfCrossing{{At: Point(0, 0), First: 0, Again: 8}}}, // Back to the origin
+	} {
+		got := SelfCrossings(Point(0, 0), mustParse(t, test.wire))
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("SelfCrossings(%q) = %+v, expected %+v", test.wire, got, test.exp)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package wires
+
+import "sort"
+
+// Brute calls f with the index into a and into b of every pair of rectangles
+// that intersect, by comparing every rectangle of a with every one of b.
+func Brute(a, b []Rect, f func(i, j int)) {
+	for i, ar := range a {
+		for j, br := range b {
+			if ar.overlaps(br) && ar.Intersects(br) {
+				f(i, j)
+			}
+		}
+	}
+}
+
+// Sweep calls f with the index into a and into b of every pair of segments that
+// intersect, in no particular order. The segments must each be horizontal,
+// vertical or diagonal, as the moves of a wire are.
+//
+// Rather than compare every pair of segments, as Brute does, Sweep moves along
+// the x axis keeping track of the horizontal and diagonal segments of each wire
+// which cover the current x. Each segment is then only compared with the
+// segments of the other wire that are currently active, and which lie along a
+// line that it crosses: horizontals are ordered by their y, and diagonals by
+// the y at which their line crosses the y axis.
+func Sweep(a, b []Rect, f func(i, j int)) {
+	// Order the events along the x axis so that, at any one x, horizontal and
+	// diagonal segments are made active before the verticals are compared with
+	// them, and then made inactive.
+	type event struct {
+		x    int64
+		kind int // One of start, vertical or end.
+		wire int // 0 for a, 1 for b.
+		i    int
+	}
+	const (
+		start = iota
+		vertical
+		end
+	)
+	wires := [2][]Rect{a, b}
+	var events []event
+	for w, rs := range wires {
+		for i, r := range rs {
+			if r.Y1 == r.Y2 || r.Slope != 0 {
+				events = append(events, event{r.X1, start, w, i}, event{r.X2, end, w, i})
+			} else {
+				events = append(events, event{r.X1, vertical, w, i})
+			}
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].x != events[j].x {
+			return events[i].x < events[j].x
+		}
+		return events[i].kind < events[j].kind
+	})
+
+	report := func(w, i, j int) {
+		if w == 0 {
+			f(i, j)
+		} else {
+			f(j, i)
+		}
+	}
+
+	// The active horizontal segments of each wire, ordered by y, and the active
+	// diagonals of each wire with a Slope of -1 and of 1, ordered by offset.
+	var active [2]sweepLine
+	var diagonals [2][2]sweepLine
+	// crossing reports the segments of the other wire, is, which r crosses.
+	crossing := func(r Rect, w, i int, is []int) {
+		for _, j := range is {
+			if r.Intersects(wires[1-w][j]) {
+				report(w, i, j)
+			}
+		}
+	}
+	for n := 0; n < len(events); {
+		// Take all of the events at this x and kind together, so that collinear
+		// vertical segments can be compared with each other.
+		m := n + 1
+		for m < len(events) && events[m].x == events[n].x && events[m].kind == events[n].kind {
+			m++
+		}
+		group := events[n:m]
+		n = m
+
+		switch group[0].kind {
+		case start:
+			for _, e := range group {
+				r := wires[e.wire][e.i]
+				other := 1 - e.wire
+				crossing(r, e.wire, e.i, crossingDiagonals(&diagonals[other], r))
+				if r.Slope != 0 {
+					crossing(r, e.wire, e.i, active[other].within(r.Y1, r.Y2))
+					diagonals[e.wire][slopeIndex(r)].insert(r.offset(), e.i)
+					continue
+				}
+				// Any active horizontal of the other wire at the same y overlaps.
+				for _, j := range active[other].within(r.Y1, r.Y1) {
+					report(e.wire, e.i, j)
+				}
+				active[e.wire].insert(r.Y1, e.i)
+			}
+		case vertical:
+			for k, e := range group {
+				r := wires[e.wire][e.i]
+				other := 1 - e.wire
+				for _, j := range active[other].within(r.Y1, r.Y2) {
+					report(e.wire, e.i, j)
+				}
+				crossing(r, e.wire, e.i, crossingDiagonals(&diagonals[other], r))
+				for _, o := range group[k+1:] {
+					if o.wire != e.wire && r.Intersects(wires[o.wire][o.i]) {
+						report(e.wire, e.i, o.i)
+					}
+				}
+			}
+		case end:
+			for _, e := range group {
+				r := wires[e.wire][e.i]
+				if r.Slope == 0 {
+					active[e.wire].remove(r.Y1, e.i)
+					continue
+				}
+				diagonals[e.wire][slopeIndex(r)].remove(r.offset(), e.i)
+			}
+		}
+	}
+}
+
+// slopeIndex returns the index into the diagonals of a sweep of the diagonal r.
+func slopeIndex(r Rect) int {
+	if r.Slope > 0 {
+		return 1
+	}
+	return 0
+}
+
+// crossingDiagonals returns the indexes of the active diagonals ds, with a
+// Slope of -1 and of 1, whose lines cross the segment r between r.X1 and r.X2.
+// Those which end before reaching r must be ruled out by the caller.
+func crossingDiagonals(ds *[2]sweepLine, r Rect) []int {
+	// Along a diagonal, y = c + x for a Slope of 1, and y = c - x for -1.
+	switch {
+	case r.Slope > 0:
+		c := r.offset()
+		return append(ds[1].within(c, c), ds[0].within(c+2*r.X1, c+2*r.X2)...)
+	case r.Slope < 0:
+		c := r.offset()
+		return append(ds[0].within(c, c), ds[1].within(c-2*r.X2, c-2*r.X1)...)
+	}
+	return append(ds[1].within(r.Y1-r.X2, r.Y2-r.X1), ds[0].within(r.Y1+r.X1, r.Y2+r.X2)...)
+}
+
+// sweepLine is the set of horizontal segments crossing the sweep, ordered by
+// their y and then their index, or of diagonals ordered by their offset.
+type sweepLine []sweepSeg
+
+type sweepSeg struct {
+	y int64
+	i int
+}
+
+func (s sweepLine) search(y int64, i int) int {
+	return sort.Search(len(s), func(k int) bool {
+		return s[k].y > y || s[k].y == y && s[k].i >= i
+	})
+}
+
+func (s *sweepLine) insert(y int64, i int) {
+	k := s.search(y, i)
+	*s = append(*s, sweepSeg{})
+	copy((*s)[k+1:], (*s)[k:])
+	(*s)[k] = sweepSeg{y, i}
+}
+
+func (s *sweepLine) remove(y int64, i int) {
+	k := s.search(y, i)
+	*s = append((*s)[:k], (*s)[k+1:]...)
+}
+
+// within returns the indexes of the segments with y from y1 to y2.
+func (s sweepLine) within(y1, y2 int64) []int {
+	var is []int
+	for k := s.search(y1, -1); k < len(s) && s[k].y <= y2; k++ {
+		is = append(is, s[k].i)
+	}
+	return is
+}
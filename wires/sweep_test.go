@@ -0,0 +1,140 @@
+package wires
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sortedCrossings returns the indexes of the crossing segments of a and b, as
+// found by cross, ordered along a and then b.
+func sortedCrossings(cross func(a, b []Rect, f func(i, j int)), a, b []Rect) [][2]int {
+	var ijs [][2]int
+	cross(a, b, func(i, j int) { ijs = append(ijs, [2]int{i, j}) })
+	sortPairs(ijs)
+	return ijs
+}
+
+func sortPairs(ijs [][2]int) {
+	sort.Slice(ijs, func(m, n int) bool {
+		if ijs[m][0] != ijs[n][0] {
+			return ijs[m][0] < ijs[n][0]
+		}
+		return ijs[m][1] < ijs[n][1]
+	})
+}
+
+// visit steps along w from the origin, returning the moves which visit each
+// point and the steps taken to first get there.
+func visit(w []Rect) (moves map[Rect][]int, steps map[Rect]int64) {
+	moves = make(map[Rect][]int)
+	steps = make(map[Rect]int64)
+	x, y, n := int64(0), int64(0), int64(0)
+	for i, e := range Ends(Point(0, 0), w) {
+		dx, dy := sign(e.X1-x), sign(e.Y1-y)
+		for {
+			p := Point(x, y)
+			if _, ok := steps[p]; !ok {
+				steps[p] = n
+			}
+			moves[p] = append(moves[p], i)
+			if x == e.X1 && y == e.Y1 {
+				break
+			}
+			x, y, n = x+dx, y+dy, n+1
+		}
+	}
+	return moves, steps
+}
+
+// trace follows wires a and b a step at a time, as the puzzle describes,
+// returning the sorted indexes of their crossing moves, and the least distance
+// and steps to a crossing other than the origin.
+func trace(a, b []Rect) (ijs [][2]int, taxi, steps int64) {
+	aMoves, aSteps := visit(a)
+	bMoves, bSteps := visit(b)
+	taxi, steps = -1, -1
+	crossed := make(map[[2]int]bool)
+	for p, is := range aMoves {
+		for _, i := range is {
+			for _, j := range bMoves[p] {
+				crossed[[2]int{i, j}] = true
+			}
+		}
+		if _, ok := bMoves[p]; !ok || p == Point(0, 0) {
+			continue
+		}
+		if d := p.TaxiDist(0, 0); taxi < 0 || d < taxi {
+			taxi = d
+		}
+		if d := aSteps[p] + bSteps[p]; steps < 0 || d < steps {
+			steps = d
+		}
+	}
+	for ij := range crossed {
+		ijs = append(ijs, ij)
+	}
+	sortPairs(ijs)
+	return ijs, taxi, steps
+}
+
+// generate returns a pair of random wires of n moves of up to maxDist, along
+// the axes for odd seeds and in any direction for even ones.
+func generate(seed int64, n int, maxDist int64) (a, b []Rect) {
+	rng := rand.New(rand.NewSource(seed))
+	dirs := Axes
+	if seed%2 == 0 {
+		dirs = Compass
+	}
+	return Generate(rng, 0, 0, n, maxDist, dirs), Generate(rng, 0, 0, n, maxDist, dirs)
+}
+
+// Sweeping must find the same crossings as brute force, and as tracing the
+// wires a step at a time.
+func TestSweepMatchesBrute(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		a, b := generate(seed, 200, 1+seed%20)
+		brute := sortedCrossings(Brute, a, b)
+		sweep := sortedCrossings(Sweep, a, b)
+		traced, _, _ := trace(a, b)
+		if !reflect.DeepEqual(brute, sweep) {
+			t.Errorf("seed %d: sweep found %d crossings, brute found %d", seed, len(sweep), len(brute))
+		}
+		if !reflect.DeepEqual(brute, traced) {
+			t.Errorf("seed %d: brute found %d crossings, tracing found %d", seed, len(brute), len(traced))
+		}
+	}
+}
+
+func BenchmarkBrute(b *testing.B) {
+	benchmarkCrossings(b, Brute)
+}
+
+func BenchmarkSweep(b *testing.B) {
+	benchmarkCrossings(b, Sweep)
+}
+
+// benchmarkCrossings finds the crossings of generated wires of up to 100k
+// moves, along the axes and in any direction.
+func benchmarkCrossings(b *testing.B, cross func(a, b []Rect, f func(i, j int))) {
+	for _, n := range []int{1000, 10000, 100000} {
+		for _, dirs := range []struct {
+			name string
+			dirs []string
+		}{{"axes", Axes}, {"compass", Compass}} {
+			b.Run(fmt.Sprintf("%s/%d", dirs.name, n), func(b *testing.B) {
+				rng := rand.New(rand.NewSource(int64(n)))
+				w1 := Generate(rng, 0, 0, n, 1000, dirs.dirs)
+				w2 := Generate(rng, 0, 0, n, 1000, dirs.dirs)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					crossings := 0
+					cross(w1, w2, func(i, j int) { crossings++ })
+					b.ReportMetric(float64(crossings), "crossings")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,351 @@
+// Package wires traces the crossed wires of day 3.
+//
+// Each wire is read as a path of comma-separated moves from the origin, such as
+// R8,U5,L5,D3, and is kept as the rectangles covering each move. Moves may be
+// Up, Down, Left or Right; North, South, East or West; or diagonally NE, SE, SW
+// or NW.
+package wires
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Rect provides rectangular constraints in the form:
+//
+//	(X1, Y1) 0---> (X2, Y1)
+//	         |   |
+//	(X1, Y2) V---+ (X2, Y2)
+//
+// A diagonal move covers only the points on one diagonal of its rectangle:
+// from (X1, Y1) to (X2, Y2) if its Slope is 1, or from (X1, Y2) to (X2, Y1) if
+// its Slope is -1. Other rectangles have a Slope of 0.
+type Rect struct {
+	X1    int64 `json:"x1"`
+	X2    int64 `json:"x2"`
+	Y1    int64 `json:"y1"`
+	Y2    int64 `json:"y2"`
+	Slope int64 `json:"slope,omitempty"`
+}
+
+// Point returns the rectangle covering just (x, y).
+func Point(x, y int64) Rect {
+	return Rect{X1: x, X2: x, Y1: y, Y2: y}
+}
+
+// Read reads a wire from each line of r, starting each from (x, y). Blank
+// lines are skipped.
+func Read(r io.Reader, x, y int64) ([][]Rect, error) {
+	var wires [][]Rect
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for line := 1; s.Scan(); line++ {
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+		w, err := parse(x, y, s.Text(), line)
+		if err != nil {
+			return nil, err
+		}
+		wires = append(wires, w)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("wires: %w", err)
+	}
+	return wires, nil
+}
+
+// Parse converts a comma-separated string of directions into a set of
+// rectangles covering the positions travelled starting from (x, y).
+func Parse(x, y int64, d string) ([]Rect, error) {
+	return parse(x, y, d, 1)
+}
+
+func parse(x, y int64, d string, line int) ([]Rect, error) {
+	dirs := strings.Split(d, ",")
+	paths := make([]Rect, len(dirs))
+	col := 1
+	for i, dir := range dirs {
+		// Allow spaces around each move, but report columns from the start of
+		// the move itself.
+		lead := len(dir) - len(strings.TrimLeft(dir, " \t\r"))
+		dir = strings.TrimSpace(dir)
+
+		v, err := move(dir)
+		if err != nil {
+			return nil, fmt.Errorf("wires: line %d, column %d: %w", line, col+lead, err)
+		}
+		paths[i] = v.add(x, y)
+		x += v.X1 + v.X2
+		y += v.Y1 + v.Y2
+		col += len(dirs[i]) + 1
+	}
+	return paths, nil
+}
+
+// move parses a single move, such as R1009 or NE12, into its vector.
+func move(dir string) (Rect, error) {
+	if dir == "" {
+		return Rect{}, errors.New("missing move")
+	}
+	n := strings.IndexFunc(dir, func(c rune) bool {
+		return !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z')
+	})
+	if n < 0 {
+		n = len(dir)
+	}
+	name, dist := dir[:n], dir[n:]
+	if _, ok := vectorBounds(name, 0); !ok {
+		return Rect{}, fmt.Errorf("unknown direction %q in %q", name, dir)
+	}
+	l, err := strconv.ParseInt(dist, 10, 64)
+	if err != nil || l < 0 || dist[0] == '+' {
+		return Rect{}, fmt.Errorf("invalid distance %q in %q", dist, dir)
+	}
+	v, _ := vectorBounds(name, l)
+	return v, nil
+}
+
+// directions are the steps taken by a move in each direction.
+var directions = map[string][2]int64{
+	"U": {0, -1}, "D": {0, 1}, "L": {-1, 0}, "R": {1, 0},
+	"N": {0, -1}, "S": {0, 1}, "W": {-1, 0}, "E": {1, 0},
+	"NE": {1, -1}, "SE": {1, 1}, "SW": {-1, 1}, "NW": {-1, -1},
+}
+
+// Axes and Compass are the names of the directions moves can take along the
+// axes, and in every direction.
+var (
+	Axes    = []string{"U", "D", "L", "R"}
+	Compass = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+)
+
+// vectorBounds returns the rectangle covering a move of l steps in direction
+// dir from the origin, or false if the direction is unknown.
+func vectorBounds(dir string, l int64) (Rect, bool) {
+	d, ok := directions[strings.ToUpper(dir)]
+	if !ok {
+		return Rect{}, false
+	}
+	dx, dy := d[0]*l, d[1]*l
+	r := Rect{X1: min(0, dx), X2: max(0, dx), Y1: min(0, dy), Y2: max(0, dy)}
+	if dx != 0 && dy != 0 {
+		r.Slope = d[0] * d[1]
+	}
+	return r, true
+}
+
+func (r Rect) add(x, y int64) Rect {
+	return Rect{X1: r.X1 + x, X2: r.X2 + x, Y1: r.Y1 + y, Y2: r.Y2 + y, Slope: r.Slope}
+}
+
+// Corners returns the points at either end of the line r: its top-left and
+// bottom-right corners or, if it has a Slope of -1, its bottom-left and
+// top-right.
+func (r Rect) Corners() (a, b Rect) {
+	if r.Slope < 0 {
+		return Point(r.X1, r.Y2), Point(r.X2, r.Y1)
+	}
+	return Point(r.X1, r.Y1), Point(r.X2, r.Y2)
+}
+
+// Each calls f with each point of the rectangle, in row order for rectangles
+// and from X1 to X2 for diagonals.
+func (r Rect) Each(f func(p Rect)) {
+	if r.Slope != 0 {
+		a, _ := r.Corners()
+		for t := int64(0); t <= r.X2-r.X1; t++ {
+			f(Point(a.X1+t, a.Y1+r.Slope*t))
+		}
+		return
+	}
+	for y := r.Y1; y <= r.Y2; y++ {
+		for x := r.X1; x <= r.X2; x++ {
+			f(Point(x, y))
+		}
+	}
+}
+
+// Steps is the number of steps required to reach one corner of the line r
+// from the other.
+func (r Rect) Steps() int64 {
+	if r.Slope != 0 {
+		return r.X2 - r.X1
+	}
+	return r.X2 - r.X1 + r.Y2 - r.Y1
+}
+
+// Intersects returns whether r and s have any point in common.
+func (r Rect) Intersects(s Rect) bool {
+	if !r.overlaps(s) {
+		return false
+	}
+	if r.Slope == 0 && s.Slope == 0 {
+		return true
+	}
+	_, ok := r.intersect(s)
+	return ok
+}
+
+// contains returns whether every point of the line s is a point of the line r.
+func (r Rect) contains(s Rect) bool {
+	a, b := s.Corners()
+	return r.on(a) && r.on(b)
+}
+
+// on returns whether the point p is a point of the line r.
+func (r Rect) on(p Rect) bool {
+	if !r.overlaps(p) {
+		return false
+	}
+	return r.Slope == 0 || p.Y1-r.Slope*p.X1 == r.offset()
+}
+
+// overlaps returns whether the rectangles r and s overlap, ignoring their
+// slopes. It's cheap enough to be inlined, to rule out most pairs of moves
+// before checking whether they intersect.
+func (r Rect) overlaps(s Rect) bool {
+	return r.X1 <= s.X2 && s.X1 <= r.X2 && r.Y1 <= s.Y2 && s.Y1 <= r.Y2
+}
+
+// Intersection returns the area common to both r and s. Note that the returned
+// Rect is only valid if r.Intersects(s).
+func (r Rect) Intersection(s Rect) Rect {
+	i, _ := r.intersect(s)
+	return i
+}
+
+func (r Rect) intersect(s Rect) (Rect, bool) {
+	box := Rect{
+		X1: max(r.X1, s.X1),
+		X2: min(r.X2, s.X2),
+		Y1: max(r.Y1, s.Y1),
+		Y2: min(r.Y2, s.Y2),
+	}
+	if box.X1 > box.X2 || box.Y1 > box.Y2 {
+		return box, false
+	}
+	if r.Slope == 0 {
+		r, s = s, r
+	}
+	if r.Slope == 0 {
+		return box, true
+	}
+
+	// Take the part of the diagonal r within both rectangles, which is all of
+	// the intersection if s is a line along either axis, or a point.
+	run, ok := r.diagonal(box)
+	if !ok || s.Slope == 0 {
+		return run, ok
+	}
+
+	// Diagonals along the same line share the run, and those along crossing
+	// lines meet at most at a point.
+	if s.Slope == r.Slope {
+		return run, s.offset() == r.offset()
+	}
+	d := s.offset() - r.offset()
+	if d%2 != 0 {
+		return box, false // They cross between points.
+	}
+	p := Point(r.Slope*d/2, 0)
+	p.Y1 = r.offset() + r.Slope*p.X1
+	p.Y2 = p.Y1
+	if p.X1 < run.X1 || p.X1 > run.X2 {
+		return box, false
+	}
+	return p, true
+}
+
+// offset returns the y at which the line of the diagonal r crosses the y axis.
+func (r Rect) offset() int64 {
+	a, _ := r.Corners()
+	return a.Y1 - r.Slope*a.X1
+}
+
+// diagonal returns the part of the line of the diagonal r within box.
+func (r Rect) diagonal(box Rect) (Rect, bool) {
+	// Along the line, y = c + Slope*x, so x = Slope*(y - c).
+	c := r.offset()
+	x1, x2 := r.Slope*(box.Y1-c), r.Slope*(box.Y2-c)
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	x1, x2 = max(x1, box.X1), min(x2, box.X2)
+	if x1 > x2 {
+		return box, false
+	}
+	if x1 == x2 {
+		return Point(x1, c+r.Slope*x1), true
+	}
+	y1, y2 := c+r.Slope*x1, c+r.Slope*x2
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	return Rect{X1: x1, X2: x2, Y1: y1, Y2: y2, Slope: r.Slope}, true
+}
+
+// TaxiDist returns the Taxi/Manhattan distance from (x, y) to the closest point
+// of the rectangle.
+func (r Rect) TaxiDist(x, y int64) int64 {
+	return r.Dist(Point(x, y))
+}
+
+// Dist returns the Taxi/Manhattan distance between rectangles r and s, ignoring
+// their slopes.
+func (r Rect) Dist(s Rect) int64 {
+	var dx int64
+	if r.X1 > s.X2 {
+		dx = r.X1 - s.X2
+	} else if s.X1 > r.X2 {
+		dx = s.X1 - r.X2
+	}
+
+	var dy int64
+	if r.Y1 > s.Y2 {
+		dy = r.Y1 - s.Y2
+	} else if s.Y1 > r.Y2 {
+		dy = s.Y1 - r.Y2
+	}
+
+	return dx + dy
+}
+
+// ChebyshevDist returns the Chebyshev distance between rectangles r and s,
+// ignoring their slopes: the most steps in either direction from one to the
+// other. It is the steps taken between two points along a move.
+func (r Rect) ChebyshevDist(s Rect) int64 {
+	var dx int64
+	if r.X1 > s.X2 {
+		dx = r.X1 - s.X2
+	} else if s.X1 > r.X2 {
+		dx = s.X1 - r.X2
+	}
+
+	var dy int64
+	if r.Y1 > s.Y2 {
+		dy = r.Y1 - s.Y2
+	} else if s.Y1 > r.Y2 {
+		dy = s.Y1 - r.Y2
+	}
+
+	return max(dx, dy)
+}
+
+func min(a, b int64) int64 {
+	if a > b {
+		return b
+	}
+	return a
+}
+
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,205 @@
+package wires
+
+import (
+	"reflect"
+	"testing"
+)
+
+// rect returns the rectangle from x1 to x2 and y1 to y2.
+func rect(x1, x2, y1, y2 int64) Rect {
+	return Rect{X1: x1, X2: x2, Y1: y1, Y2: y2}
+}
+
+// diag returns the diagonal of the rectangle from x1 to x2 and y1 to y2.
+func diag(x1, x2, y1, y2, slope int64) Rect {
+	return Rect{X1: x1, X2: x2, Y1: y1, Y2: y2, Slope: slope}
+}
+
+// mustParse parses the wire w, failing the test if it's malformed.
+func mustParse(t testing.TB, w string) []Rect {
+	t.Helper()
+	r, err := Parse(0, 0, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// points returns the set of points of r.
+func points(r Rect) map[Rect]bool {
+	ps := make(map[Rect]bool)
+	r.Each(func(p Rect) { ps[p] = true })
+	return ps
+}
+
+func TestIntersects(t *testing.T) {
+	for _, test := range []struct {
+		a, b Rect
+		exp  bool
+	}{
+		{rect(0, 0, 0, 0), rect(0, 0, 0, 0), true},  // Same points
+		{rect(0, 0, 0, 0), rect(1, 1, 1, 1), false}, // Different points
+
+		{rect(0, 0, 1, 1), rect(0, 0, 0, 2), true},  // Enclosed vertical line
+		{rect(0, 0, 0, 2), rect(0, 0, -1, 1), true}, // Overlapping vertical line
+		{rect(0, 0, 0, 2), rect(0, 0, 2, 3), true},  // Touching vertical line
+
+		{rect(1, 1, 0, 0), rect(0, 2, 0, 0), true},  // Enclosed horizontal line
+		{rect(0, 2, 0, 0), rect(-1, 1, 0, 0), true}, // Overlapping horizontal line
+		{rect(0, 2, 0, 0), rect(2, 3, 0, 0), true},  // Touching horizontal line
+
+		{rect(0, 4, 2, 2), rect(2, 2, 0, 4), true},         // Crossing lines
+		{rect(0, 4, 2, 2), rect(5, 5, 0, 4), false},        // Lines passing by
+		{diag(0, 4, 0, 4, 1), rect(0, 4, 2, 2), true},      // Diagonal crossing a line
+		{diag(0, 4, 0, 4, 1), rect(0, 1, 3, 3), false},     // Diagonal passing a line within its rectangle
+		{diag(0, 4, 0, 4, 1), diag(0, 4, 0, 4, -1), true},  // Diagonals crossing at a point
+		{diag(0, 3, 0, 3, 1), diag(0, 3, 0, 3, -1), false}, // Diagonals crossing between points
+		{diag(0, 4, 0, 4, 1), diag(2, 6, 2, 6, 1), true},   // Overlapping diagonals
+		{diag(0, 4, 0, 4, 1), diag(0, 4, 1, 5, 1), false},  // Parallel diagonals
+	} {
+		if got := test.a.Intersects(test.b); got != test.exp {
+			t.Errorf("%+v.Intersects(%+v) = %t, expected %t", test.a, test.b, got, test.exp)
+		}
+		if got := test.b.Intersects(test.a); got != test.exp {
+			t.Errorf("%+v.Intersects(%+v) = %t, expected %t", test.b, test.a, got, test.exp)
+		}
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	for _, test := range []struct {
+		a, b, exp Rect
+	}{
+		{rect(0, 4, 2, 2), rect(2, 2, 0, 4), Point(2, 2)},
+		{rect(0, 4, 2, 2), rect(2, 6, 2, 2), rect(2, 4, 2, 2)},
+		{diag(0, 4, 0, 4, 1), rect(0, 4, 2, 2), Point(2, 2)},
+		{diag(0, 4, 0, 4, 1), diag(0, 4, 0, 4, -1), Point(2, 2)},
+		{diag(0, 4, 0, 4, 1), diag(2, 6, 2, 6, 1), diag(2, 4, 2, 4, 1)},
+		{diag(0, 4, 0, 4, -1), diag(3, 6, -2, 1, -1), diag(3, 4, 0, 1, -1)},
+	} {
+		if got := test.a.Intersection(test.b); got != test.exp {
+			t.Errorf("%+v.Intersection(%+v) = %+v, expected %+v", test.a, test.b, got, test.exp)
+		}
+		if got := test.b.Intersection(test.a); got != test.exp {
+			t.Errorf("%+v.Intersection(%+v) = %+v, expected %+v", test.b, test.a, got, test.exp)
+		}
+	}
+}
+
+func TestDist(t *testing.T) {
+	for _, test := range []struct {
+		a, b Rect
+		exp  int64
+	}{
+		{Point(0, 0), Point(3, -4), 7},
+		{rect(0, 4, 2, 2), Point(-1, 0), 3},
+		{rect(2, 2, 0, 4), rect(4, 6, 7, 7), 5},
+		{diag(0, 4, 0, 4, 1), Point(6, -1), 3},
+	} {
+		if got := test.a.Dist(test.b); got != test.exp {
+			t.Errorf("%+v.Dist(%+v) = %d, expected %d", test.a, test.b, got, test.exp)
+		}
+		if got := test.b.Dist(test.a); got != test.exp {
+			t.Errorf("%+v.Dist(%+v) = %d, expected %d", test.b, test.a, got, test.exp)
+		}
+	}
+}
+
+func TestSteps(t *testing.T) {
+	for _, test := range []struct {
+		r   Rect
+		exp int64
+	}{
+		{Point(3, -4), 0},
+		{rect(0, 4, 2, 2), 4},
+		{rect(2, 2, 0, 4), 4},
+		{diag(0, 4, 0, 4, 1), 4},
+		{diag(-3, 0, 2, 5, -1), 3},
+	} {
+		if got := test.r.Steps(); got != test.exp {
+			t.Errorf("%+v.Steps() = %d, expected %d", test.r, got, test.exp)
+		}
+		if n := int64(len(points(test.r))); n != test.exp+1 {
+			t.Errorf("%+v has %d points, expected %d", test.r, n, test.exp+1)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		err string
+	}{
+		{"R8,U5,L5,D3", ""},
+		{"r8, u5", ""},
+		{"R8,U5,X5", "wires: line 1, column 7: unknown direction \"X\" in \"X5\""},
+		{"R8,,D3", "wires: line 1, column 4: missing move"},
+		{"R8,U", "wires: line 1, column 4: invalid distance \"\" in \"U\""},
+		{"R8,U-5", "wires: line 1, column 4: invalid distance \"-5\" in \"U-5\""},
+		{"R8,U5x", "wires: line 1, column 4: invalid distance \"5x\" in \"U5x\""},
+		{"NE5,sw3,N2,E1", ""},
+		{"R8,UR5", "wires: line 1, column 4: unknown direction \"UR\" in \"UR5\""},
+		{"R8,5", "wires: line 1, column 4: unknown direction \"\" in \"5\""},
+	} {
+		_, err := Parse(0, 0, test.in)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != test.err {
+			t.Errorf("Parse(%q) error %q, expected %q", test.in, got, test.err)
+		}
+	}
+
+	w := mustParse(t, "R8,U5,NW2")
+	if exp := []Rect{rect(0, 8, 0, 0), rect(8, 8, -5, 0), diag(6, 8, -7, -5, 1)}; !reflect.DeepEqual(w, exp) {
+		t.Errorf("Parse = %+v, expected %+v", w, exp)
+	}
+}
+
+// FuzzIntersects checks pairs of moves of up to 10 steps in any direction,
+// starting from within 10 of the origin.
+func FuzzIntersects(f *testing.F) {
+	f.Add(int8(0), int8(0), uint8(0), uint8(4), int8(2), int8(-2), uint8(4), uint8(4))
+	f.Add(int8(0), int8(0), uint8(1), uint8(3), int8(0), int8(-3), uint8(3), uint8(3))
+	f.Add(int8(-1), int8(5), uint8(7), uint8(6), int8(1), int8(3), uint8(3), uint8(10))
+	f.Fuzz(func(t *testing.T, ax, ay int8, ad, al uint8, bx, by int8, bd, bl uint8) {
+		move := func(x, y int8, d, l uint8) Rect {
+			v, _ := vectorBounds(Compass[int(d)%len(Compass)], int64(l%11))
+			return v.add(int64(x%11), int64(y%11))
+		}
+		a, b := move(ax, ay, ad, al), move(bx, by, bd, bl)
+		ap, bp := points(a), points(b)
+
+		intersects := a.Intersects(b)
+		if intersects != b.Intersects(a) {
+			t.Fatalf("%+v.Intersects(%+v) differs when swapped", a, b)
+		}
+		shared := 0
+		for p := range ap {
+			if bp[p] {
+				shared++
+			}
+		}
+		if intersects != (shared > 0) {
+			t.Fatalf("%+v.Intersects(%+v) = %t, but they share %d points", a, b, intersects, shared)
+		}
+		if !intersects {
+			return
+		}
+
+		at := a.Intersection(b)
+		if at != b.Intersection(a) {
+			t.Fatalf("%+v.Intersection(%+v) differs when swapped", a, b)
+		}
+		atp := points(at)
+		for p := range atp {
+			if !ap[p] || !bp[p] {
+				t.Fatalf("%+v.Intersection(%+v) = %+v, which has %+v outside of both", a, b, at, p)
+			}
+		}
+		if len(atp) != shared {
+			t.Fatalf("%+v.Intersection(%+v) = %+v, with %d points rather than the %d shared", a, b, at, len(atp), shared)
+		}
+	})
+}